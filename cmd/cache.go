@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/erebusbat/markdown-tool/internal/cache"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and manage the cache of fetched titles and API lookups",
+}
+
+var cacheStatsKeys bool
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show cache location and entry counts",
+	Long: `Show the cache location and entry counts. With --keys, also list the key
+of every entry, which is what cache get takes.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := openCache()
+		if err != nil {
+			return err
+		}
+
+		stats, err := c.Stats()
+		if err != nil {
+			return fmt.Errorf("failed to read cache: %w", err)
+		}
+
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "Directory: %s\n", stats.Dir)
+		fmt.Fprintf(out, "Entries:   %d (%d negative, %d expired)\n", stats.Entries, stats.Negative, stats.Expired)
+		fmt.Fprintf(out, "Size:      %d bytes\n", stats.Bytes)
+
+		sources := make([]string, 0, len(stats.BySource))
+		for source := range stats.BySource {
+			sources = append(sources, source)
+		}
+		sort.Strings(sources)
+		for _, source := range sources {
+			fmt.Fprintf(out, "  %-10s %d\n", source, stats.BySource[source])
+		}
+
+		if !cacheStatsKeys {
+			return nil
+		}
+		entries, err := c.Entries()
+		if err != nil {
+			return fmt.Errorf("failed to read cache: %w", err)
+		}
+		fmt.Fprintln(out, "Keys:")
+		for _, entry := range entries {
+			fmt.Fprintf(out, "  %-10s %s\n", entry.Source, entry.URL)
+		}
+		return nil
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove every cached entry",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := openCache()
+		if err != nil {
+			return err
+		}
+
+		if err := c.Clear(); err != nil {
+			return fmt.Errorf("failed to clear cache: %w", err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Cleared %s\n", c.Dir())
+		return nil
+	},
+}
+
+var cacheGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Show cached entries for a cache key",
+	Long: `Show the cached entries stored under a key. The key is the URL that was
+fetched, which is not always the one pasted: page titles use the page URL,
+YouTube titles the canonical watch or playlist URL, GitHub lookups the REST
+API URL (e.g. https://api.github.com/repos/org/repo/issues/1) and JIRA
+discovery the site's project list URL (/rest/api/3/project on Cloud,
+/rest/api/2/project on Server). List the keys with cache stats --keys.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := openCache()
+		if err != nil {
+			return err
+		}
+
+		entries := c.Lookup(args[0])
+		if len(entries) == 0 {
+			return fmt.Errorf("no cache entries for %s (list the keys with cache stats --keys)", cache.CanonicalURL(args[0]))
+		}
+
+		out := cmd.OutOrStdout()
+		now := time.Now()
+		for _, entry := range entries {
+			state := "expires " + entry.ExpiresAt.Format(time.RFC3339)
+			if entry.Expired(now) {
+				state = "expired " + entry.ExpiresAt.Format(time.RFC3339)
+			}
+
+			if entry.Negative {
+				fmt.Fprintf(out, "%s: <failed: %s> (%s)\n", entry.Source, entry.Error, state)
+			} else {
+				fmt.Fprintf(out, "%s: %s (%s)\n", entry.Source, entry.Value, state)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	cacheStatsCmd.Flags().BoolVar(&cacheStatsKeys, "keys", false, "also list the key of every entry")
+	cacheCmd.AddCommand(cacheStatsCmd, cacheClearCmd, cacheGetCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+// openCache loads the configuration and returns the cache it describes
+func openCache() (*cache.Cache, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	c := cache.FromConfig(cfg)
+	if c == nil {
+		return nil, fmt.Errorf("cache is disabled")
+	}
+	return c, nil
+}
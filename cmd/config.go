@@ -0,0 +1,355 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/erebusbat/markdown-tool/internal/config"
+	"github.com/erebusbat/markdown-tool/internal/jira"
+	"github.com/erebusbat/markdown-tool/pkg/types"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	initGitHubOrg    string
+	initGitHubRepo   string
+	initJIRADomain   string
+	initJIRAProjects []string
+	initForce        bool
+	initNoDetect     bool
+	showEffective    bool
+	pathAll          bool
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Create, inspect and validate the configuration file",
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the path of the configuration file",
+	Long: `Print the path of the user configuration file. With --all, list every
+file that contributes to the loaded configuration, lowest precedence first.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !pathAll {
+			path, err := config.Path(cfgFile)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), path)
+			return nil
+		}
+
+		sources, err := configSources()
+		if err != nil {
+			return err
+		}
+		for _, source := range sources {
+			fmt.Fprintf(cmd.OutOrStdout(), "%-8s %s\n", source.Kind, source.Path)
+		}
+		return nil
+	},
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a new configuration file",
+	Long: `Create a new configuration file. The GitHub org and repo are suggested
+from the git remote of the current directory, or from github.user in your
+global git config. Flags override the suggestions; when run interactively
+without them, each value is prompted for.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := config.Path(cfgFile)
+		if err != nil {
+			return err
+		}
+
+		// Start from what the current repository and git config suggest;
+		// flags override individual values
+		opts := config.InitOptions{}
+		if !initNoDetect {
+			if dir, err := os.Getwd(); err == nil {
+				opts = config.DetectInit(dir)
+			}
+		}
+		if initGitHubOrg != "" {
+			opts.GitHubOrg = initGitHubOrg
+		}
+		if initGitHubRepo != "" {
+			opts.GitHubRepo = initGitHubRepo
+		}
+		if initJIRADomain != "" {
+			opts.JIRADomain = initJIRADomain
+		}
+		if len(initJIRAProjects) > 0 {
+			opts.JIRAProjects = initJIRAProjects
+		}
+
+		if isInteractive() && !hasInitValueFlags(cmd) {
+			in := bufio.NewReader(cmd.InOrStdin())
+			out := cmd.OutOrStdout()
+			opts.GitHubOrg = prompt(in, out, "GitHub default org", opts.GitHubOrg)
+			opts.GitHubRepo = prompt(in, out, "GitHub default repo", opts.GitHubRepo)
+			opts.JIRADomain = prompt(in, out, "JIRA domain (e.g. https://example.atlassian.net)", opts.JIRADomain)
+			projects := prompt(in, out, "JIRA projects (comma separated)", strings.Join(opts.JIRAProjects, ","))
+			opts.JIRAProjects = splitList(projects)
+		}
+
+		if err := config.WriteInitial(path, opts, initForce); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s\n", path)
+
+		return config.ValidateFile(path)
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:          "validate",
+	Short:        "Check every configuration file for unknown keys and invalid values",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sources, err := configSources()
+		if err != nil {
+			return err
+		}
+
+		failed := 0
+		for _, source := range sources {
+			if source.Kind == config.SourceRepo {
+				ignored, err := config.RepoIgnoredKeys(source.Path)
+				if err == nil && len(ignored) > 0 {
+					fmt.Fprintf(cmd.ErrOrStderr(), "%s: warning: ignored in a repo-local file: %s\n", source.Path, strings.Join(ignored, ", "))
+				}
+			}
+			if err := config.ValidateFile(source.Path); err != nil {
+				fmt.Fprintln(cmd.ErrOrStderr(), err)
+				failed++
+				continue
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s: OK\n", source.Path)
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d configuration file(s) have problems", failed)
+		}
+		return nil
+	},
+}
+
+var configDiscoverCmd = &cobra.Command{
+	Use:   "discover",
+	Short: "Fetch and cache each JIRA site's project list",
+	Long: `Fetch the project list of each JIRA site from its REST API and cache it,
+so keys of discovered projects link. Needs jira.discovery.enabled.
+
+Parsing never waits on the network; a run whose cached lists are missing or
+expired starts this command in the background instead.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		if !cfg.JIRA.Discovery.Enabled {
+			return fmt.Errorf("JIRA project discovery is off; set jira.discovery.enabled")
+		}
+
+		resolver := jira.ResolverFromConfig(cfg)
+		failed := 0
+		for _, instance := range jira.Instances(cfg) {
+			projects := resolver.Discover(cmd.Context(), instance)
+			if len(projects) == 0 {
+				fmt.Fprintf(cmd.ErrOrStderr(), "%s: no projects discovered\n", instance.Domain)
+				failed++
+				continue
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s: %d projects\n", instance.Domain, len(projects))
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d JIRA site(s) returned no projects", failed)
+		}
+		return nil
+	},
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the configuration file, or the effective configuration",
+	Long: `Print the configuration file as written. With --effective, print the
+merged configuration after the active profile and overrides are applied.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+
+		if !showEffective {
+			path, err := config.Path(cfgFile)
+			if err != nil {
+				return err
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			_, err = out.Write(data)
+			return err
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		if cfg.Profile != "" {
+			fmt.Fprintf(out, "# profile: %s\n", cfg.Profile)
+		}
+		// Profiles have already been applied; don't repeat them
+		cfg.Profiles = nil
+		redactSecrets(cfg)
+
+		encoder := yaml.NewEncoder(out)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(cfg); err != nil {
+			return err
+		}
+		return encoder.Close()
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:          "edit",
+	Short:        "Open the configuration file in $VISUAL or $EDITOR and validate it",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := config.Path(cfgFile)
+		if err != nil {
+			return err
+		}
+
+		editor := os.Getenv("VISUAL")
+		if editor == "" {
+			editor = os.Getenv("EDITOR")
+		}
+		if editor == "" {
+			editor = "vi"
+		}
+
+		// Editors are often configured with arguments, e.g. "code --wait"
+		fields := strings.Fields(editor)
+		editCmd := exec.Command(fields[0], append(fields[1:], path)...)
+		editCmd.Stdin = os.Stdin
+		editCmd.Stdout = os.Stdout
+		editCmd.Stderr = os.Stderr
+		if err := editCmd.Run(); err != nil {
+			return fmt.Errorf("editor failed: %w", err)
+		}
+
+		if err := config.ValidateFile(path); err != nil {
+			var validationErr *config.ValidationError
+			if errors.As(err, &validationErr) {
+				fmt.Fprintln(cmd.ErrOrStderr(), "Saved, but the configuration has problems:")
+			}
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s: OK\n", path)
+		return nil
+	},
+}
+
+// redactSecrets hides API tokens so the effective configuration can be
+// shared
+func redactSecrets(cfg *types.Config) {
+	const redacted = "(redacted)"
+	if cfg.GitHub.API.Token != "" {
+		cfg.GitHub.API.Token = redacted
+	}
+	for i := range cfg.GitHub.Hosts {
+		if cfg.GitHub.Hosts[i].Token != "" {
+			cfg.GitHub.Hosts[i].Token = redacted
+		}
+	}
+	if cfg.JIRA.Discovery.Token != "" {
+		cfg.JIRA.Discovery.Token = redacted
+	}
+	for i := range cfg.JIRA.Instances {
+		if cfg.JIRA.Instances[i].Token != "" {
+			cfg.JIRA.Instances[i].Token = redacted
+		}
+	}
+}
+
+func init() {
+	configInitCmd.Flags().StringVar(&initGitHubOrg, "github-org", "", "default GitHub organization")
+	configInitCmd.Flags().StringVar(&initGitHubRepo, "github-repo", "", "default GitHub repository")
+	configInitCmd.Flags().StringVar(&initJIRADomain, "jira-domain", "", "JIRA base URL, e.g. https://example.atlassian.net")
+	configInitCmd.Flags().StringSliceVar(&initJIRAProjects, "jira-projects", nil, "JIRA project keys, e.g. PLAT,SPEED")
+	configInitCmd.Flags().BoolVar(&initForce, "force", false, "overwrite an existing configuration file")
+	configInitCmd.Flags().BoolVar(&initNoDetect, "no-detect", false, "don't suggest values from git remotes and git config")
+
+	configPathCmd.Flags().BoolVar(&pathAll, "all", false, "list every configuration file in the order applied")
+
+	configShowCmd.Flags().BoolVar(&showEffective, "effective", false, "show the merged configuration after profile and overrides")
+
+	configCmd.AddCommand(configPathCmd, configInitCmd, configValidateCmd, configDiscoverCmd, configShowCmd, configEditCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// hasInitValueFlags reports whether any setting was given on the command line
+func hasInitValueFlags(cmd *cobra.Command) bool {
+	for _, name := range []string{"github-org", "github-repo", "jira-domain", "jira-projects"} {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// configSources lists the config files for the current flags
+func configSources() ([]config.Source, error) {
+	return config.Sources(config.Options{File: cfgFile})
+}
+
+// isInteractive reports whether stdin is a terminal
+func isInteractive() bool {
+	return isTerminal(os.Stdin)
+}
+
+// prompt asks for a value, returning def when the answer is empty
+func prompt(in *bufio.Reader, out io.Writer, label, def string) string {
+	if def != "" {
+		fmt.Fprintf(out, "%s [%s]: ", label, def)
+	} else {
+		fmt.Fprintf(out, "%s: ", label)
+	}
+
+	answer, _ := in.ReadString('\n')
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return def
+	}
+	return answer
+}
+
+// splitList splits a comma separated list, dropping empty items
+func splitList(s string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/erebusbat/markdown-tool/internal/clipboard"
+	"github.com/erebusbat/markdown-tool/internal/history"
+	"github.com/erebusbat/markdown-tool/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyJSON  bool
+	historyLimit int
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List, search and re-copy previous transformations",
+}
+
+var historyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recent transformations, newest first",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := openHistory(true)
+		if err != nil {
+			return err
+		}
+
+		entries, err := store.Entries()
+		if err != nil {
+			return fmt.Errorf("failed to read history: %w", err)
+		}
+		return printHistory(cmd.OutOrStdout(), numberEntries(entries))
+	},
+}
+
+var historySearchCmd = &cobra.Command{
+	Use:   "search <term>",
+	Short: "Find transformations whose input, output, type or profile contain term",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := openHistory(true)
+		if err != nil {
+			return err
+		}
+
+		entries, err := store.Entries()
+		if err != nil {
+			return fmt.Errorf("failed to read history: %w", err)
+		}
+		matches, err := store.Search(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read history: %w", err)
+		}
+
+		// Keep the numbers from the full list so they work with `history copy`
+		numbered := numberEntries(entries)
+		found := make([]numberedEntry, 0, len(matches))
+		for _, entry := range numbered {
+			for _, match := range matches {
+				if entry.Entry == match {
+					found = append(found, entry)
+					break
+				}
+			}
+		}
+		return printHistory(cmd.OutOrStdout(), found)
+	},
+}
+
+var historyCopyCmd = &cobra.Command{
+	Use:   "copy <n>",
+	Short: "Copy the output of entry n (1 is the most recent) to the clipboard",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid entry number %q", args[0])
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		store, err := historyStore(cfg, true)
+		if err != nil {
+			return err
+		}
+		entries, err := store.Entries()
+		if err != nil {
+			return fmt.Errorf("failed to read history: %w", err)
+		}
+		if n > len(entries) {
+			return fmt.Errorf("no history entry %d (%d recorded)", n, len(entries))
+		}
+
+		if err := clipboard.Replace(clipboardBackend, undoBuffer(cfg), entries[n-1].Output); err != nil {
+			return fmt.Errorf("failed to write clipboard: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), entries[n-1].Output)
+		return nil
+	},
+}
+
+var historyClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete the history log",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Clearing works even with history disabled, to remove what was
+		// recorded before
+		store, err := openHistory(false)
+		if err != nil {
+			return err
+		}
+
+		if err := store.Clear(); err != nil {
+			return fmt.Errorf("failed to clear history: %w", err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Cleared %s\n", store.Path())
+		return nil
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{historyListCmd, historySearchCmd} {
+		c.Flags().BoolVar(&historyJSON, "json", false, "print entries as JSON")
+		c.Flags().IntVarP(&historyLimit, "limit", "n", 20, "maximum number of entries to show (0 for all)")
+	}
+
+	historyCmd.AddCommand(historyListCmd, historySearchCmd, historyCopyCmd, historyClearCmd)
+	rootCmd.AddCommand(historyCmd)
+}
+
+// openHistory loads the configuration and returns the history store it
+// describes. With requireEnabled, a disabled history is an error.
+func openHistory(requireEnabled bool) (*history.Store, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return historyStore(cfg, requireEnabled)
+}
+
+func historyStore(cfg *types.Config, requireEnabled bool) (*history.Store, error) {
+	if cfg.History.Dir == "" || (requireEnabled && cfg.History.Disabled) {
+		return nil, fmt.Errorf("history is disabled")
+	}
+	return history.New(cfg.History.Dir, history.Options{
+		MaxEntries: cfg.History.MaxEntries,
+		MaxAge:     cfg.History.MaxAge,
+		HideInput:  cfg.History.HideInput,
+	}), nil
+}
+
+// numberedEntry is a history entry with its position for `history copy`
+type numberedEntry struct {
+	N int `json:"n"`
+	history.Entry
+}
+
+func numberEntries(entries []history.Entry) []numberedEntry {
+	numbered := make([]numberedEntry, len(entries))
+	for i, entry := range entries {
+		numbered[i] = numberedEntry{N: i + 1, Entry: entry}
+	}
+	return numbered
+}
+
+func printHistory(out io.Writer, entries []numberedEntry) error {
+	if historyLimit > 0 && len(entries) > historyLimit {
+		entries = entries[:historyLimit]
+	}
+
+	if historyJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	}
+
+	for _, entry := range entries {
+		input := entry.Input
+		if input == "" {
+			input = "sha256:" + entry.InputHash[:12]
+		}
+		fmt.Fprintf(out, "%3d  %s  %-14s %s\n", entry.N, entry.Time.Local().Format(time.DateTime), entry.Type, input)
+		fmt.Fprintf(out, "     → %s\n", history.Preview(entry.Output))
+	}
+	return nil
+}
@@ -1,24 +1,37 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
-	"github.com/atotto/clipboard"
+	"github.com/erebusbat/markdown-tool/internal/clipboard"
 	"github.com/erebusbat/markdown-tool/internal/config"
-	"github.com/erebusbat/markdown-tool/internal/parser"
-	"github.com/erebusbat/markdown-tool/internal/writer"
+	"github.com/erebusbat/markdown-tool/internal/history"
+	"github.com/erebusbat/markdown-tool/internal/jira"
+	"github.com/erebusbat/markdown-tool/internal/pipeline"
 	"github.com/erebusbat/markdown-tool/pkg/types"
 	"github.com/spf13/cobra"
 )
 
 var (
-	verbose bool
-	cfgFile string
+	verbose      bool
+	cfgFile      string
+	profileName  string
+	parseTimeout time.Duration
+	noNetwork    bool
+	copyOutput   bool
+
+	// clipboardBackend is the clipboard read for input and written by --copy
+	clipboardBackend clipboard.Backend = clipboard.System{}
 )
 
 var rootCmd = &cobra.Command{
@@ -44,14 +57,88 @@ func Execute() {
 
 func init() {
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.config/markdown-tool/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file to use instead of the system, user and repo-local files")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "config profile to use (default from $"+config.ProfileEnvVar+" or match rules)")
+	rootCmd.PersistentFlags().BoolVar(&noNetwork, "no-network", false, "disable all network lookups (titles, oEmbed)")
+	rootCmd.PersistentFlags().DurationVar(&parseTimeout, "timeout", 0, "global parse deadline, e.g. 800ms (overrides parse_timeout in config)")
+	rootCmd.Flags().BoolVarP(&copyOutput, "copy", "c", false, "also copy the output to the clipboard (restore the previous contents with undo)")
+}
+
+// loadConfig loads configuration honouring the global flags
+func loadConfig() (*types.Config, error) {
+	cfg, sources, err := config.LoadWithOptions(config.Options{
+		File:    cfgFile,
+		Profile: profileName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	applyFlags(cfg)
+	if verbose && cfg.Profile != "" {
+		log.Printf("using profile %q", cfg.Profile)
+	}
+	if cfgFile == "" && len(sources) == 0 && isTerminal(os.Stderr) {
+		noConfigHint(os.Stderr)
+	}
+	return cfg, nil
+}
+
+// noConfigHintMarker records in the state directory that the no-config hint
+// has been shown
+const noConfigHintMarker = "no-config-hint-shown"
+
+// noConfigHint suggests config init the first time the tool runs without a
+// config. If the marker can't be written the hint isn't shown at all,
+// rather than on every run.
+func noConfigHint(w io.Writer) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return
+	}
+	marker := filepath.Join(dir, noConfigHintMarker)
+	if _, err := os.Stat(marker); err == nil {
+		return
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return
+	}
+	if err := os.WriteFile(marker, nil, 0600); err != nil {
+		return
+	}
+	fmt.Fprintln(w, "markdown-tool: no configuration found; GitHub defaults and JIRA keys are off.")
+	fmt.Fprintln(w, "Run `markdown-tool config init` to create one from this repository's git remote.")
+}
+
+// applyFlags overrides configuration with the global flags
+func applyFlags(cfg *types.Config) {
+	cfg.Verbose = verbose
+	if noNetwork {
+		cfg.Network.Mode = types.NetworkModeOff
+	}
+	if parseTimeout > 0 {
+		cfg.ParseTimeout = parseTimeout
+	}
+}
+
+// isTerminal reports whether f is a terminal. The null device is also a
+// character device, so it is ruled out explicitly.
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	if err != nil || (stat.Mode()&os.ModeCharDevice) == 0 {
+		return false
+	}
+	if null, err := os.Stat(os.DevNull); err == nil && os.SameFile(stat, null) {
+		return false
+	}
+	return true
 }
 
 func run() error {
 	// Load configuration
-	cfg, err := config.Load(cfgFile)
+	cfg, err := loadConfig()
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return err
 	}
 
 	// Get input from stdin or clipboard
@@ -74,39 +161,67 @@ func run() error {
 		return nil // No input after preprocessing
 	}
 
-	// Parse input
-	parsers := parser.GetParsers(cfg)
-	contexts := make([]*types.ParseContext, 0)
+	// Parse input concurrently; Ctrl-C cancels any in-flight network lookups
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	result, err := pipeline.New(cfg).Run(ctx, input)
+	if err != nil {
+		return err
+	}
+	recordHistory(history.FromConfig(cfg), cfg, input, result)
 
-	for _, p := range parsers {
-		if ctx, err := p.Parse(input); err == nil && ctx != nil {
-			contexts = append(contexts, ctx)
+	if copyOutput {
+		if err := clipboard.Replace(clipboardBackend, undoBuffer(cfg), result.Output); err != nil {
+			return fmt.Errorf("failed to write clipboard: %w", err)
 		}
 	}
 
-	// Vote on best writer
-	writers := writer.GetWriters(cfg)
-	bestWriter, bestScore := writer.Vote(writers, contexts)
+	fmt.Print(result.Output)
+	discoverInBackground(cfg)
+	return nil
+}
 
-	if bestWriter == nil || bestScore == 0 {
-		// No writer wants to handle this, output verbatim
-		fmt.Print(input)
-		return nil
+// discoverInBackground starts `config discover` as a separate process when
+// a JIRA project list is missing or expired, so this run exits without
+// waiting on the network. Keys of discovered projects link from the next run.
+func discoverInBackground(cfg *types.Config) {
+	if !jira.ResolverFromConfig(cfg).Stale() {
+		return
 	}
-
-	// Generate output
-	if len(contexts) == 0 {
-		fmt.Print(input)
-		return nil
+	exe, err := os.Executable()
+	if err != nil {
+		return
 	}
 
-	output, err := bestWriter.Write(contexts[0])
-	if err != nil {
-		return fmt.Errorf("failed to write output: %w", err)
+	args := []string{"config", "discover"}
+	if cfgFile != "" {
+		args = append(args, "--config", cfgFile)
+	}
+	if profileName != "" {
+		args = append(args, "--profile", profileName)
+	}
+	// Standard streams are left unset, so the child holds none of ours open
+	// and a caller reading our output sees it end when we exit
+	discover := exec.Command(exe, args...)
+	if err := discover.Start(); err != nil {
+		if verbose {
+			log.Printf("jira: failed to start discovery: %v", err)
+		}
+		return
 	}
+	_ = discover.Process.Release()
+}
 
-	fmt.Print(output)
-	return nil
+// recordHistory logs a transformation. Input passed through unchanged is
+// not recorded, and failures never affect the output.
+func recordHistory(store *history.Store, cfg *types.Config, input string, result pipeline.Result) {
+	if result.Output == input {
+		return
+	}
+	if err := store.Record(input, result.Type, result.Output, cfg.Profile); err != nil && cfg.Verbose {
+		log.Printf("history: %v", err)
+	}
 }
 
 func getInput() (string, error) {
@@ -126,7 +241,7 @@ func getInput() (string, error) {
 	}
 
 	// No stdin input, try clipboard
-	return clipboard.ReadAll()
+	return clipboardBackend.Read()
 }
 
 // preprocessTelURIs converts tel: URIs to phone numbers that can be processed by existing parsers
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestNoConfigHint_ShownOnce(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	var first, second bytes.Buffer
+	noConfigHint(&first)
+	noConfigHint(&second)
+
+	if !strings.Contains(first.String(), "config init") {
+		t.Errorf("first hint = %q, want a config init suggestion", first.String())
+	}
+	if second.Len() != 0 {
+		t.Errorf("second hint = %q, want nothing", second.String())
+	}
+}
@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/erebusbat/markdown-tool/internal/config"
+	"github.com/erebusbat/markdown-tool/internal/history"
+	"github.com/erebusbat/markdown-tool/internal/jira"
+	"github.com/erebusbat/markdown-tool/internal/pipeline"
+	"github.com/erebusbat/markdown-tool/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// maxRequestBytes bounds the input accepted by /transform
+const maxRequestBytes = 1 << 20
+
+// discoveryInterval is how often serve checks for expired JIRA project lists
+const discoveryInterval = time.Hour
+
+var (
+	serveAddr    string
+	serveNoWatch bool
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run a local HTTP server that transforms text",
+	Long: `Run a local HTTP server for editor and launcher integrations.
+
+  POST /transform  the request body is the input; the response is the markdown
+  GET  /status     JSON with the active profile, config files, reload count
+                   and the last reload error
+
+The configuration is reloaded whenever one of its files changes, or on
+SIGHUP. An invalid edit is logged and the previous configuration stays in
+effect; requests already in flight finish with the configuration they
+started with.
+
+Requests must address the server by its listen address or a loopback name,
+and may only come from loopback origins, so web pages can't reach it.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		srv := &server{addr: serveAddr, started: time.Now(), watching: !serveNoWatch}
+		srv.watcher = config.NewWatcher(config.Options{File: cfgFile, Profile: profileName}, func(cfg *types.Config) error {
+			applyFlags(cfg)
+			srv.current.Store(&snapshot{
+				pipeline: pipeline.New(cfg),
+				history:  history.FromConfig(cfg),
+			})
+			go jira.ResolverFromConfig(cfg).Refresh(ctx)
+			return nil
+		})
+		if err := srv.watcher.Reload(); err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if srv.watching {
+			go func() {
+				if err := srv.watcher.Run(ctx); err != nil {
+					log.Printf("config: %v", err)
+				}
+			}()
+		}
+		go srv.reloadOnHangup(ctx)
+		go srv.refreshDiscovery(ctx)
+
+		httpServer := &http.Server{
+			Addr:              serveAddr,
+			Handler:           srv.routes(),
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = httpServer.Shutdown(shutdownCtx)
+		}()
+
+		log.Printf("listening on http://%s", serveAddr)
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:7410", "address to listen on")
+	serveCmd.Flags().BoolVar(&serveNoWatch, "no-watch", false, "don't reload the configuration when its files change")
+	rootCmd.AddCommand(serveCmd)
+}
+
+// server holds the current snapshot. Each request loads the pointer once, so
+// a reload never changes the configuration under a transform in flight.
+type server struct {
+	// addr is the address listened on, which requests must name as Host
+	addr     string
+	current  atomic.Pointer[snapshot]
+	watcher  *config.Watcher
+	started  time.Time
+	watching bool
+}
+
+// snapshot is everything built from one configuration
+type snapshot struct {
+	pipeline *pipeline.Pipeline
+	history  *history.Store
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /transform", s.handleTransform)
+	mux.HandleFunc("GET /status", s.handleStatus)
+	return s.localOnly(mux)
+}
+
+// localOnly rejects requests that a web page could have made: a Host other
+// than the listen address or a loopback name for its port, which is how DNS
+// rebinding shows up, or a cross-origin Origin header
+func (s *server) localOnly(next http.Handler) http.Handler {
+	_, port, _ := net.SplitHostPort(s.addr)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, hostPort, err := net.SplitHostPort(r.Host)
+		if r.Host != s.addr && (err != nil || hostPort != port || !isLoopback(host)) {
+			http.Error(w, "forbidden host", http.StatusForbidden)
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" {
+			u, err := url.Parse(origin)
+			if err != nil || !isLoopback(u.Hostname()) {
+				http.Error(w, "forbidden origin", http.StatusForbidden)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isLoopback reports whether host is localhost or a loopback address
+func isLoopback(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func (s *server) handleTransform(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	input := preprocessTelURIs(strings.TrimSpace(string(body)))
+	if input == "" {
+		return
+	}
+
+	current := s.current.Load()
+	result, err := current.pipeline.Run(r.Context(), input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	recordHistory(current.history, current.pipeline.Config(), input, result)
+	_, _ = io.WriteString(w, result.Output)
+}
+
+func (s *server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	status := struct {
+		config.WatchStatus
+		Started  time.Time `json:"started"`
+		Watching bool      `json:"watching"`
+	}{
+		WatchStatus: s.watcher.Status(),
+		Started:     s.started,
+		Watching:    s.watching,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(status)
+}
+
+// reloadOnHangup reloads the configuration on SIGHUP until ctx is done
+func (s *server) reloadOnHangup(ctx context.Context) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			if err := s.watcher.Reload(); err != nil {
+				log.Printf("config: reload failed, keeping previous configuration: %v", err)
+			}
+		}
+	}
+}
+
+// refreshDiscovery refetches expired JIRA project lists for the current
+// configuration until ctx is done, since lookups only read the cache. A
+// reload also refreshes them straight away.
+func (s *server) refreshDiscovery(ctx context.Context) {
+	ticker := time.NewTicker(discoveryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			jira.ResolverFromConfig(s.current.Load().pipeline.Config()).Refresh(ctx)
+		}
+	}
+}
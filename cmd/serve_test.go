@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/erebusbat/markdown-tool/internal/pipeline"
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+func TestServer_LocalOnly(t *testing.T) {
+	srv := &server{addr: "127.0.0.1:7410"}
+	srv.current.Store(&snapshot{pipeline: pipeline.New(&types.Config{})})
+	handler := srv.routes()
+
+	tests := []struct {
+		name   string
+		host   string
+		origin string
+		status int
+	}{
+		{"Listen address", "127.0.0.1:7410", "", http.StatusOK},
+		{"Localhost on the same port", "localhost:7410", "", http.StatusOK},
+		{"IPv6 loopback", "[::1]:7410", "", http.StatusOK},
+		{"Loopback origin", "127.0.0.1:7410", "http://localhost:3000", http.StatusOK},
+		{"Rebound hostname", "attacker.example:7410", "", http.StatusForbidden},
+		{"Other port", "127.0.0.1:80", "", http.StatusForbidden},
+		{"Missing port", "localhost", "", http.StatusForbidden},
+		{"Cross-origin page", "127.0.0.1:7410", "https://attacker.example", http.StatusForbidden},
+		{"Opaque origin", "127.0.0.1:7410", "null", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/transform", strings.NewReader("hello"))
+			req.Host = tt.host
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d (%s)", rec.Code, tt.status, strings.TrimSpace(rec.Body.String()))
+			}
+		})
+	}
+}
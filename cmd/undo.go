@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/erebusbat/markdown-tool/internal/clipboard"
+	"github.com/erebusbat/markdown-tool/internal/history"
+	"github.com/erebusbat/markdown-tool/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var undoList bool
+
+var undoCmd = &cobra.Command{
+	Use:   "undo [n]",
+	Short: "Restore clipboard contents the tool overwrote",
+	Long: `Restore the clipboard as it was before markdown-tool last replaced it.
+
+Each time the tool writes the clipboard (--copy, history copy) the previous
+contents are saved. undo restores the most recent; undo n restores an older
+one, and --list shows what is saved and, in a terminal, asks which to restore.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		undo := undoBuffer(cfg)
+		if undo == nil {
+			return fmt.Errorf("no cache directory for clipboard undo")
+		}
+
+		n := 1
+		if len(args) == 1 {
+			if n, err = strconv.Atoi(args[0]); err != nil || n < 1 {
+				return fmt.Errorf("invalid entry number %q", args[0])
+			}
+		}
+
+		out := cmd.OutOrStdout()
+		if undoList {
+			saved, err := undo.List()
+			if err != nil {
+				return fmt.Errorf("failed to read clipboard undo: %w", err)
+			}
+			if len(saved) == 0 {
+				fmt.Fprintln(out, "Nothing to undo")
+				return nil
+			}
+			for i, entry := range saved {
+				fmt.Fprintf(out, "%3d  %s  %s\n", i+1, entry.Time.Local().Format(time.DateTime), history.Preview(entry.Text))
+			}
+			if !isInteractive() {
+				return nil
+			}
+
+			answer := prompt(bufio.NewReader(cmd.InOrStdin()), out, "Restore which", "")
+			if answer == "" {
+				return nil
+			}
+			if n, err = strconv.Atoi(answer); err != nil || n < 1 {
+				return fmt.Errorf("invalid entry number %q", answer)
+			}
+		}
+
+		entry, err := undo.Take(n)
+		if err != nil {
+			return err
+		}
+		if err := clipboardBackend.Write(entry.Text); err != nil {
+			// Put it back so the contents aren't lost
+			_ = undo.Push(entry.Text)
+			return fmt.Errorf("failed to write clipboard: %w", err)
+		}
+		fmt.Fprintf(out, "Restored clipboard: %s\n", history.Preview(entry.Text))
+		return nil
+	},
+}
+
+func init() {
+	undoCmd.Flags().BoolVarP(&undoList, "list", "l", false, "list saved clipboard contents and choose one to restore")
+	rootCmd.AddCommand(undoCmd)
+}
+
+// undoBuffer returns the clipboard undo buffer in the cache directory, or
+// nil when there is no cache directory. It is kept even with the lookup
+// cache disabled.
+func undoBuffer(cfg *types.Config) *clipboard.Undo {
+	if cfg.Cache.Dir == "" {
+		return nil
+	}
+	return clipboard.NewUndo(cfg.Cache.Dir, clipboard.DefaultUndoSize)
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/erebusbat/markdown-tool/internal/config"
+	"github.com/erebusbat/markdown-tool/internal/fixture"
+	"github.com/erebusbat/markdown-tool/internal/pipeline"
+	"github.com/erebusbat/markdown-tool/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var verifyUpdate bool
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify <dir|file>...",
+	Short: "Run the examples in markdown fixture files and report differences",
+	Long: `Run every Input / Expected Output example found in markdown files and
+report the ones whose output differs, with a diff.
+
+A fixture file may name the config it needs in YAML front matter:
+
+  ---
+  config: ../config.yaml
+  ---
+
+Files without one use --config, or the normal configuration. Fixtures run
+with the network and lookup cache off so results are reproducible. With
+--update, failing expectations are rewritten with the actual output.`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		paths, err := fixturePaths(args)
+		if err != nil {
+			return err
+		}
+
+		v := &verifier{
+			out:       cmd.OutOrStdout(),
+			pipelines: make(map[string]*pipeline.Pipeline),
+		}
+		for _, path := range paths {
+			if err := v.verifyFile(cmd.Context(), path); err != nil {
+				return err
+			}
+		}
+
+		fmt.Fprintf(v.out, "\n%d passed, %d failed", v.passed, v.failed)
+		if v.updated > 0 {
+			fmt.Fprintf(v.out, ", %d updated", v.updated)
+		}
+		fmt.Fprintln(v.out)
+
+		if v.failed > v.updated {
+			return fmt.Errorf("%d example(s) failed", v.failed-v.updated)
+		}
+		return nil
+	},
+}
+
+func init() {
+	verifyCmd.Flags().BoolVar(&verifyUpdate, "update", false, "rewrite failing expectations with the actual output")
+	rootCmd.AddCommand(verifyCmd)
+}
+
+// fixturePaths expands directories into the markdown files below them
+func fixturePaths(args []string) ([]string, error) {
+	paths := make([]string, 0)
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			paths = append(paths, arg)
+			continue
+		}
+
+		err = filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.EqualFold(filepath.Ext(path), ".md") {
+				paths = append(paths, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+type verifier struct {
+	out io.Writer
+	// pipelines caches one pipeline per config file; "" is the default
+	pipelines map[string]*pipeline.Pipeline
+
+	passed, failed, updated int
+}
+
+func (v *verifier) verifyFile(ctx context.Context, path string) error {
+	file, err := fixture.Load(path)
+	if err != nil {
+		return err
+	}
+	if len(file.Cases) == 0 {
+		return nil
+	}
+
+	p, err := v.pipeline(file.Config)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	actual := make(map[int]string)
+	for i, c := range file.Cases {
+		input := preprocessTelURIs(strings.TrimSpace(c.Input))
+		output := ""
+		if input != "" {
+			if output, err = p.Transform(ctx, input); err != nil {
+				output = "error: " + err.Error()
+			}
+		}
+
+		if strings.TrimSpace(output) == strings.TrimSpace(c.Expected) {
+			v.passed++
+			if verbose {
+				fmt.Fprintln(v.out, strings.TrimSpace(fmt.Sprintf("PASS %s:%d %s", path, c.Line, c.Name)))
+			}
+			continue
+		}
+
+		v.failed++
+		actual[i] = output
+		fmt.Fprintln(v.out, strings.TrimSpace(fmt.Sprintf("FAIL %s:%d %s", path, c.Line, c.Name)))
+		for _, line := range strings.Split(strings.TrimSuffix(fixture.Diff(c.Expected, output), "\n"), "\n") {
+			fmt.Fprintf(v.out, "    %s\n", line)
+		}
+	}
+
+	if !verifyUpdate || len(actual) == 0 {
+		return nil
+	}
+
+	content, err := file.Update(actual)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return err
+	}
+	v.updated += len(actual)
+	fmt.Fprintf(v.out, "updated %d example(s) in %s\n", len(actual), path)
+	return nil
+}
+
+// pipeline returns the pipeline for a fixture's config file, or for the
+// configuration given on the command line when configFile is empty
+func (v *verifier) pipeline(configFile string) (*pipeline.Pipeline, error) {
+	if p, ok := v.pipelines[configFile]; ok {
+		return p, nil
+	}
+
+	var cfg *types.Config
+	var err error
+	if configFile != "" {
+		// A fixture's config is all that applies to it
+		cfg, _, err = config.LoadWithOptions(config.Options{File: configFile, Isolated: true})
+	} else {
+		cfg, _, err = config.LoadWithOptions(config.Options{File: cfgFile, Profile: profileName})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	applyFlags(cfg)
+	// Keep results reproducible: no network lookups, no cached ones and no
+	// repository taken from the current directory
+	cfg.Network.Mode = types.NetworkModeOff
+	cfg.Cache.Disabled = true
+	cfg.NoGitRemote = true
+
+	p := pipeline.New(cfg)
+	v.pipelines[configFile] = p
+	return p, nil
+}
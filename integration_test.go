@@ -1,10 +1,15 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"testing"
 
+	"github.com/erebusbat/markdown-tool/internal/config"
+	"github.com/erebusbat/markdown-tool/internal/fixture"
 	"github.com/erebusbat/markdown-tool/internal/parser"
 	"github.com/erebusbat/markdown-tool/internal/writer"
 	"github.com/erebusbat/markdown-tool/pkg/types"
@@ -560,13 +565,7 @@ func processInput(t *testing.T, cfg *types.Config, input string) string {
 
 	// Parse input
 	parsers := parser.GetParsers(cfg)
-	contexts := make([]*types.ParseContext, 0)
-
-	for _, p := range parsers {
-		if ctx, err := p.Parse(input); err == nil && ctx != nil {
-			contexts = append(contexts, ctx)
-		}
-	}
+	contexts := parser.ParseAll(context.Background(), parsers, input, parser.Timeout(cfg))
 
 	// Vote on best writer
 	writers := writer.GetWriters(cfg)
@@ -720,3 +719,42 @@ func TestURLDomainMappingIntegration(t *testing.T) {
 		})
 	}
 }
+
+// TestExamples runs the Input / Expected Output examples under examples/,
+// the same corpus checked by `markdown-tool verify examples`
+func TestExamples(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("examples", "*", "*.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no example files found")
+	}
+
+	for _, path := range paths {
+		file, err := fixture.Load(path)
+		if err != nil {
+			t.Fatalf("fixture.Load(%s) error = %v", path, err)
+		}
+
+		if file.Config == "" {
+			t.Fatalf("%s: examples must name their config in front matter", path)
+		}
+		cfg, _, err := config.LoadWithOptions(config.Options{File: file.Config, Isolated: true})
+		if err != nil {
+			t.Fatalf("%s: config.LoadWithOptions(%q) error = %v", path, file.Config, err)
+		}
+		cfg.Network.Mode = types.NetworkModeOff
+		cfg.Cache.Disabled = true
+		cfg.NoGitRemote = true
+
+		for _, c := range file.Cases {
+			t.Run(fmt.Sprintf("%s:%d", path, c.Line), func(t *testing.T) {
+				got := processInput(t, cfg, strings.TrimSpace(c.Input))
+				if strings.TrimSpace(got) != strings.TrimSpace(c.Expected) {
+					t.Errorf("%s\n%s", c.Name, fixture.Diff(c.Expected, got))
+				}
+			})
+		}
+	}
+}
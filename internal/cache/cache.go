@@ -0,0 +1,447 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/erebusbat/markdown-tool/internal/fetch"
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+// Sources namespace cache entries and select their TTL
+const (
+	SourceYouTube = "youtube"
+	SourceTitle   = "title"
+	SourceGitHub  = "github"
+	SourceJIRA    = "jira"
+)
+
+// Defaults applied when Options leaves a value unset
+const (
+	DefaultTTL         = 24 * time.Hour
+	DefaultNegativeTTL = time.Hour
+	DefaultMaxEntries  = 1000
+)
+
+// DefaultTTLs holds the built-in time-to-live for each known source
+var DefaultTTLs = map[string]time.Duration{
+	SourceYouTube: 30 * 24 * time.Hour,
+	SourceTitle:   7 * 24 * time.Hour,
+	// Issue and pull request state changes often
+	SourceGitHub: time.Hour,
+	// Project lists rarely change
+	SourceJIRA: 24 * time.Hour,
+}
+
+// ErrCachedFailure is returned by Fetch when a previous lookup failed and
+// that failure is still cached
+var ErrCachedFailure = errors.New("cached failure")
+
+// Options configures TTLs and size bounds for a Cache
+type Options struct {
+	TTLs        map[string]time.Duration
+	NegativeTTL time.Duration
+	MaxEntries  int
+}
+
+// Entry is a single cached lookup result
+type Entry struct {
+	Source    string    `json:"source"`
+	URL       string    `json:"url"`
+	Value     string    `json:"value,omitempty"`
+	Negative  bool      `json:"negative,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Expired reports whether the entry is past its expiry at now
+func (e *Entry) Expired(now time.Time) bool {
+	return !now.Before(e.ExpiresAt)
+}
+
+// Stats summarises the contents of a cache
+type Stats struct {
+	Dir      string
+	Entries  int
+	Negative int
+	Expired  int
+	Bytes    int64
+	BySource map[string]int
+}
+
+// Cache is an on-disk key/value store for network lookups keyed by source
+// and canonical URL. Each entry is a separate file written atomically, so
+// several processes can share a cache directory safely. A nil *Cache is
+// valid and caches nothing.
+type Cache struct {
+	dir     string
+	options Options
+	now     func() time.Time
+}
+
+// New creates a cache rooted at dir, filling in defaults for unset options
+func New(dir string, opts Options) *Cache {
+	ttls := make(map[string]time.Duration, len(DefaultTTLs)+len(opts.TTLs))
+	for source, ttl := range DefaultTTLs {
+		ttls[source] = ttl
+	}
+	for source, ttl := range opts.TTLs {
+		ttls[strings.ToLower(source)] = ttl
+	}
+	opts.TTLs = ttls
+
+	if opts.NegativeTTL <= 0 {
+		opts.NegativeTTL = DefaultNegativeTTL
+	}
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = DefaultMaxEntries
+	}
+
+	return &Cache{dir: dir, options: opts, now: time.Now}
+}
+
+// FromConfig returns the cache described by cfg, or nil when caching is
+// disabled or no cache directory is known
+func FromConfig(cfg *types.Config) *Cache {
+	if cfg == nil || cfg.Cache.Disabled || cfg.Cache.Dir == "" {
+		return nil
+	}
+
+	return New(cfg.Cache.Dir, Options{
+		TTLs:        cfg.Cache.TTL,
+		NegativeTTL: cfg.Cache.NegativeTTL,
+		MaxEntries:  cfg.Cache.MaxEntries,
+	})
+}
+
+// DefaultDir returns the cache directory under the user's XDG cache dir
+func DefaultDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "markdown-tool"), nil
+}
+
+// Dir returns the directory the cache is stored in
+func (c *Cache) Dir() string {
+	if c == nil {
+		return ""
+	}
+	return c.dir
+}
+
+// Get returns the unexpired entry for source and rawURL, if any
+func (c *Cache) Get(source, rawURL string) (*Entry, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	entry, err := c.read(c.path(source, rawURL))
+	if err != nil || entry.Expired(c.now()) {
+		return nil, false
+	}
+	return entry, true
+}
+
+// Lookup returns every entry for rawURL across all known sources, including
+// expired ones
+func (c *Cache) Lookup(rawURL string) []*Entry {
+	if c == nil {
+		return nil
+	}
+
+	entries := make([]*Entry, 0)
+	for _, source := range c.sources() {
+		if entry, err := c.read(c.path(source, rawURL)); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Set stores a successful lookup
+func (c *Cache) Set(source, rawURL, value string) error {
+	if c == nil {
+		return nil
+	}
+
+	now := c.now()
+	return c.write(&Entry{
+		Source:    source,
+		URL:       CanonicalURL(rawURL),
+		Value:     value,
+		CreatedAt: now,
+		ExpiresAt: now.Add(c.ttl(source)),
+	})
+}
+
+// SetNegative stores a failed lookup so it is not retried until NegativeTTL passes
+func (c *Cache) SetNegative(source, rawURL string, cause error) error {
+	if c == nil {
+		return nil
+	}
+
+	now := c.now()
+	entry := &Entry{
+		Source:    source,
+		URL:       CanonicalURL(rawURL),
+		Negative:  true,
+		CreatedAt: now,
+		ExpiresAt: now.Add(c.options.NegativeTTL),
+	}
+	if cause != nil {
+		entry.Error = cause.Error()
+	}
+	return c.write(entry)
+}
+
+// Fetch returns the cached value for source and rawURL, calling fn and
+// caching its result on a miss. Empty results and errors are cached
+// negatively, except when ctx was cancelled or the request was blocked by
+// the network policy, which says nothing about the URL itself.
+func (c *Cache) Fetch(ctx context.Context, source, rawURL string, fn func(context.Context) (string, error)) (string, error) {
+	if c == nil {
+		return fn(ctx)
+	}
+
+	if entry, ok := c.Get(source, rawURL); ok {
+		if entry.Negative {
+			return "", ErrCachedFailure
+		}
+		return entry.Value, nil
+	}
+
+	value, err := fn(ctx)
+
+	switch {
+	case err == nil && value != "":
+		_ = c.Set(source, rawURL, value)
+	case ctx.Err() == nil && !errors.Is(err, fetch.ErrDenied):
+		_ = c.SetNegative(source, rawURL, err)
+	}
+	return value, err
+}
+
+// Stats walks the cache and summarises its contents
+func (c *Cache) Stats() (Stats, error) {
+	stats := Stats{Dir: c.Dir(), BySource: make(map[string]int)}
+	if c == nil {
+		return stats, nil
+	}
+
+	now := c.now()
+	err := c.walk(func(path string, info os.FileInfo) {
+		entry, err := c.read(path)
+		if err != nil {
+			return
+		}
+		stats.Entries++
+		stats.Bytes += info.Size()
+		stats.BySource[entry.Source]++
+		if entry.Negative {
+			stats.Negative++
+		}
+		if entry.Expired(now) {
+			stats.Expired++
+		}
+	})
+	return stats, err
+}
+
+// Entries returns every entry in the cache, including expired ones, sorted
+// by source and then URL
+func (c *Cache) Entries() ([]*Entry, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	entries := make([]*Entry, 0)
+	err := c.walk(func(path string, info os.FileInfo) {
+		if entry, err := c.read(path); err == nil {
+			entries = append(entries, entry)
+		}
+	})
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Source != entries[j].Source {
+			return entries[i].Source < entries[j].Source
+		}
+		return entries[i].URL < entries[j].URL
+	})
+	return entries, err
+}
+
+// Clear removes every cached entry
+func (c *Cache) Clear() error {
+	if c == nil {
+		return nil
+	}
+
+	for _, source := range c.sources() {
+		if err := os.RemoveAll(filepath.Join(c.dir, source)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CanonicalURL normalises rawURL so equivalent URLs share a cache key:
+// scheme and host are lower-cased, default ports, fragments and trailing
+// slashes are dropped and query parameters are sorted.
+func CanonicalURL(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return strings.TrimSpace(rawURL)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	if (u.Scheme == "http" && u.Port() == "80") || (u.Scheme == "https" && u.Port() == "443") {
+		u.Host = u.Hostname()
+	}
+	u.Fragment = ""
+	u.RawFragment = ""
+	if u.Path != "/" {
+		u.Path = strings.TrimSuffix(u.Path, "/")
+		u.RawPath = ""
+	}
+	// Encode sorts query parameters by key
+	u.RawQuery = u.Query().Encode()
+
+	return u.String()
+}
+
+func (c *Cache) ttl(source string) time.Duration {
+	if ttl, ok := c.options.TTLs[source]; ok && ttl > 0 {
+		return ttl
+	}
+	return DefaultTTL
+}
+
+func (c *Cache) sources() []string {
+	sources := make([]string, 0, len(c.options.TTLs))
+	for source := range c.options.TTLs {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	return sources
+}
+
+func (c *Cache) path(source, rawURL string) string {
+	sum := sha256.Sum256([]byte(CanonicalURL(rawURL)))
+	return filepath.Join(c.dir, source, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *Cache) read(path string) (*Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("corrupt cache entry %s: %w", path, err)
+	}
+	return &entry, nil
+}
+
+// write stores entry via a temp file and rename so readers in other
+// processes never observe a partially written file
+func (c *Cache) write(entry *Entry) error {
+	path := c.path(entry.Source, entry.URL)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	return c.evict()
+}
+
+// evict removes the oldest entries, by modification time, once the cache
+// holds more than MaxEntries. Only directory listings are read, so a write
+// stays cheap however large the cache is; expired entries age out with the
+// rest. Files removed concurrently by another process are ignored.
+func (c *Cache) evict() error {
+	type file struct {
+		path    string
+		modTime time.Time
+	}
+
+	files := make([]file, 0)
+	err := c.walk(func(path string, info os.FileInfo) {
+		files = append(files, file{path: path, modTime: info.ModTime()})
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(files) <= c.options.MaxEntries {
+		return nil
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+	for _, f := range files[:len(files)-c.options.MaxEntries] {
+		_ = os.Remove(f.path)
+	}
+	return nil
+}
+
+// walk calls fn for every entry file in the cache
+func (c *Cache) walk(fn func(path string, info os.FileInfo)) error {
+	for _, source := range c.sources() {
+		dirEntries, err := os.ReadDir(filepath.Join(c.dir, source))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, de := range dirEntries {
+			if de.IsDir() || !strings.HasSuffix(de.Name(), ".json") {
+				continue
+			}
+			info, err := de.Info()
+			if err != nil {
+				continue
+			}
+			fn(filepath.Join(c.dir, source, de.Name()), info)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/erebusbat/markdown-tool/internal/fetch"
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+func TestCanonicalURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Lowercases scheme and host", "HTTPS://WWW.Example.COM/Path", "https://www.example.com/Path"},
+		{"Drops default port", "https://example.com:443/a", "https://example.com/a"},
+		{"Keeps custom port", "http://example.com:8080/a", "http://example.com:8080/a"},
+		{"Drops fragment", "https://example.com/a#section", "https://example.com/a"},
+		{"Drops trailing slash", "https://example.com/a/", "https://example.com/a"},
+		{"Sorts query", "https://example.com/watch?v=1&a=2", "https://example.com/watch?a=2&v=1"},
+		{"Non-URL unchanged", "  not a url ", "not a url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanonicalURL(tt.input); got != tt.expected {
+				t.Errorf("CanonicalURL(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCache_SetGet(t *testing.T) {
+	c := New(t.TempDir(), Options{})
+
+	if err := c.Set(SourceYouTube, "https://www.youtube.com/watch?v=abc#t=10", "A Video"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	entry, ok := c.Get(SourceYouTube, "https://WWW.youtube.com/watch?v=abc")
+	if !ok {
+		t.Fatal("Get() missed an equivalent canonical URL")
+	}
+	if entry.Value != "A Video" {
+		t.Errorf("Value = %q, want %q", entry.Value, "A Video")
+	}
+
+	if _, ok := c.Get(SourceTitle, "https://www.youtube.com/watch?v=abc"); ok {
+		t.Error("Get() should not share entries across sources")
+	}
+}
+
+func TestCache_TTLPerSource(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := New(t.TempDir(), Options{TTLs: map[string]time.Duration{SourceTitle: time.Hour}})
+	c.now = func() time.Time { return now }
+
+	_ = c.Set(SourceTitle, "https://example.com", "Example")
+	_ = c.Set(SourceYouTube, "https://youtu.be/abc", "Video")
+
+	now = now.Add(2 * time.Hour)
+	if _, ok := c.Get(SourceTitle, "https://example.com"); ok {
+		t.Error("title entry should have expired after its configured TTL")
+	}
+	if _, ok := c.Get(SourceYouTube, "https://youtu.be/abc"); !ok {
+		t.Error("youtube entry should still be fresh under its default TTL")
+	}
+}
+
+func TestCache_FetchNegativeCaching(t *testing.T) {
+	c := New(t.TempDir(), Options{})
+	calls := 0
+	failing := func(context.Context) (string, error) {
+		calls++
+		return "", errors.New("boom")
+	}
+
+	if _, err := c.Fetch(context.Background(), SourceTitle, "https://example.com", failing); err == nil {
+		t.Fatal("Fetch() should surface the first failure")
+	}
+	if _, err := c.Fetch(context.Background(), SourceTitle, "https://example.com", failing); !errors.Is(err, ErrCachedFailure) {
+		t.Errorf("Fetch() error = %v, want ErrCachedFailure", err)
+	}
+	if calls != 1 {
+		t.Errorf("fetch function called %d times, want 1", calls)
+	}
+
+	entries := c.Lookup("https://example.com")
+	if len(entries) != 1 || !entries[0].Negative || entries[0].Error != "boom" {
+		t.Errorf("Lookup() = %+v, want one negative entry", entries)
+	}
+}
+
+func TestCache_FetchDoesNotCacheCancellation(t *testing.T) {
+	c := New(t.TempDir(), Options{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _ = c.Fetch(ctx, SourceTitle, "https://example.com", func(ctx context.Context) (string, error) {
+		return "", ctx.Err()
+	})
+
+	if entries := c.Lookup("https://example.com"); len(entries) != 0 {
+		t.Errorf("Lookup() = %+v, want no entries after a cancelled fetch", entries)
+	}
+}
+
+func TestCache_FetchHit(t *testing.T) {
+	c := New(t.TempDir(), Options{})
+	calls := 0
+	fn := func(context.Context) (string, error) {
+		calls++
+		return "Title", nil
+	}
+
+	for i := 0; i < 3; i++ {
+		title, err := c.Fetch(context.Background(), SourceTitle, "https://example.com", fn)
+		if err != nil || title != "Title" {
+			t.Fatalf("Fetch() = %q, %v; want Title, nil", title, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("fetch function called %d times, want 1", calls)
+	}
+}
+
+func TestCache_Eviction(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := New(t.TempDir(), Options{MaxEntries: 3})
+	c.now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		if err := c.Set(SourceTitle, fmt.Sprintf("https://example.com/%d", i), "title"); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+
+	stats, err := c.Stats()
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if stats.Entries != 3 {
+		t.Errorf("Entries = %d, want 3", stats.Entries)
+	}
+}
+
+func TestCache_EvictionRemovesOldestFiles(t *testing.T) {
+	c := New(t.TempDir(), Options{MaxEntries: 2})
+	for i := 0; i < 2; i++ {
+		if err := c.Set(SourceTitle, fmt.Sprintf("https://example.com/%d", i), "title"); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(c.path(SourceTitle, "https://example.com/1"), old, old); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+
+	if err := c.Set(SourceTitle, "https://example.com/2", "title"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if _, ok := c.Get(SourceTitle, "https://example.com/1"); ok {
+		t.Error("oldest entry survived eviction")
+	}
+	for _, u := range []string{"https://example.com/0", "https://example.com/2"} {
+		if _, ok := c.Get(SourceTitle, u); !ok {
+			t.Errorf("Get(%q) missed a newer entry", u)
+		}
+	}
+}
+
+func TestCache_ConcurrentAccess(t *testing.T) {
+	dir := t.TempDir()
+
+	// Separate instances stand in for separate processes sharing a directory
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c := New(dir, Options{})
+			for j := 0; j < 20; j++ {
+				_ = c.Set(SourceTitle, "https://example.com/shared", fmt.Sprintf("writer %d", i))
+				if entry, ok := c.Get(SourceTitle, "https://example.com/shared"); ok && entry.Value == "" {
+					t.Errorf("Get() observed a partially written entry")
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if _, ok := New(dir, Options{}).Get(SourceTitle, "https://example.com/shared"); !ok {
+		t.Error("Get() missed the shared entry after concurrent writes")
+	}
+}
+
+func TestCache_StatsAndClear(t *testing.T) {
+	c := New(t.TempDir(), Options{})
+	_ = c.Set(SourceTitle, "https://example.com/a", "A")
+	_ = c.Set(SourceYouTube, "https://youtu.be/b", "B")
+	_ = c.SetNegative(SourceTitle, "https://example.com/c", nil)
+
+	stats, err := c.Stats()
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if stats.Entries != 3 || stats.Negative != 1 || stats.BySource[SourceTitle] != 2 {
+		t.Errorf("Stats() = %+v, want 3 entries, 1 negative, 2 titles", stats)
+	}
+
+	entries, err := c.Entries()
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	keys := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		keys = append(keys, entry.Source+" "+entry.URL)
+	}
+	want := []string{"title https://example.com/a", "title https://example.com/c", "youtube https://youtu.be/b"}
+	if strings.Join(keys, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Entries() keys = %q, want %q", keys, want)
+	}
+
+	if err := c.Clear(); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if stats, _ := c.Stats(); stats.Entries != 0 {
+		t.Errorf("Entries after Clear() = %d, want 0", stats.Entries)
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	if FromConfig(&types.Config{}) != nil {
+		t.Error("FromConfig() without a directory should return nil")
+	}
+	if FromConfig(&types.Config{Cache: types.CacheConfig{Dir: t.TempDir(), Disabled: true}}) != nil {
+		t.Error("FromConfig() with caching disabled should return nil")
+	}
+	if FromConfig(&types.Config{Cache: types.CacheConfig{Dir: t.TempDir()}}) == nil {
+		t.Error("FromConfig() with a directory should return a cache")
+	}
+
+	// A nil cache passes lookups straight through
+	var c *Cache
+	title, err := c.Fetch(context.Background(), SourceTitle, "https://example.com", func(context.Context) (string, error) {
+		return "Direct", nil
+	})
+	if title != "Direct" || err != nil {
+		t.Errorf("nil Cache Fetch() = %q, %v; want Direct, nil", title, err)
+	}
+}
+
+func TestCache_FetchDoesNotCacheDenials(t *testing.T) {
+	c := New(t.TempDir(), Options{})
+
+	_, err := c.Fetch(context.Background(), SourceTitle, "https://example.com", func(context.Context) (string, error) {
+		return "", fmt.Errorf("%w: network is off", fetch.ErrDenied)
+	})
+	if !errors.Is(err, fetch.ErrDenied) {
+		t.Errorf("Fetch() error = %v, want ErrDenied", err)
+	}
+	if entries := c.Lookup("https://example.com"); len(entries) != 0 {
+		t.Errorf("Lookup() = %+v, want no entries after a denied request", entries)
+	}
+}
@@ -0,0 +1,69 @@
+package clipboard
+
+import (
+	"sync"
+
+	"github.com/atotto/clipboard"
+)
+
+// Backend reads and writes a clipboard
+type Backend interface {
+	Read() (string, error)
+	Write(text string) error
+}
+
+// System is the operating system clipboard
+type System struct{}
+
+// Read returns the clipboard contents
+func (System) Read() (string, error) {
+	return clipboard.ReadAll()
+}
+
+// Write replaces the clipboard contents
+func (System) Write(text string) error {
+	return clipboard.WriteAll(text)
+}
+
+// Fake is an in-memory clipboard for tests
+type Fake struct {
+	mu   sync.Mutex
+	text string
+	// Err, when set, is returned by Read and Write
+	Err error
+}
+
+// NewFake returns a Fake holding text
+func NewFake(text string) *Fake {
+	return &Fake{text: text}
+}
+
+// Read returns the fake clipboard contents
+func (f *Fake) Read() (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.text, f.Err
+}
+
+// Write replaces the fake clipboard contents
+func (f *Fake) Write(text string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return f.Err
+	}
+	f.text = text
+	return nil
+}
+
+// Replace writes text to the clipboard, first saving the current contents
+// to undo so they can be restored. Nothing is saved when the clipboard
+// already holds text or can't be read; a nil undo saves nothing.
+func Replace(backend Backend, undo *Undo, text string) error {
+	if current, err := backend.Read(); err == nil && current != text && current != "" {
+		if err := undo.Push(current); err != nil {
+			return err
+		}
+	}
+	return backend.Write(text)
+}
@@ -0,0 +1,117 @@
+package clipboard
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestReplace(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  string
+		text     string
+		expected []string
+	}{
+		{"Saves previous contents", "PROJ-1", "[PROJ-1](https://x/browse/PROJ-1)", []string{"PROJ-1"}},
+		{"Skips unchanged contents", "same", "same", []string{}},
+		{"Skips empty clipboard", "", "out", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := NewFake(tt.current)
+			undo := NewUndo(t.TempDir(), 0)
+
+			if err := Replace(fake, undo, tt.text); err != nil {
+				t.Fatalf("Replace() error = %v", err)
+			}
+			if got, _ := fake.Read(); got != tt.text {
+				t.Errorf("clipboard = %q, want %q", got, tt.text)
+			}
+
+			saved, err := undo.List()
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if len(saved) != len(tt.expected) {
+				t.Fatalf("List() = %+v, want %v", saved, tt.expected)
+			}
+			for i, text := range tt.expected {
+				if saved[i].Text != text {
+					t.Errorf("saved[%d] = %q, want %q", i, saved[i].Text, text)
+				}
+			}
+		})
+	}
+}
+
+func TestReplace_WriteError(t *testing.T) {
+	fake := NewFake("before")
+	fake.Err = errors.New("no clipboard")
+
+	if err := Replace(fake, nil, "after"); err == nil {
+		t.Error("Replace() should return the write error")
+	}
+}
+
+func TestUndo_Ring(t *testing.T) {
+	undo := NewUndo(t.TempDir(), 3)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	undo.now = func() time.Time { return now }
+
+	for _, text := range []string{"a", "b", "b", "c", "d"} {
+		if err := undo.Push(text); err != nil {
+			t.Fatalf("Push(%q) error = %v", text, err)
+		}
+		now = now.Add(time.Minute)
+	}
+
+	saved, err := undo.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	got := make([]string, 0, len(saved))
+	for _, entry := range saved {
+		got = append(got, entry.Text)
+	}
+	if len(got) != 3 || got[0] != "d" || got[1] != "c" || got[2] != "b" {
+		t.Errorf("List() = %v, want [d c b]", got)
+	}
+
+	info, err := os.Stat(undo.path)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("buffer permissions = %o, want 600", perm)
+	}
+}
+
+func TestUndo_Take(t *testing.T) {
+	undo := NewUndo(t.TempDir(), 0)
+	if _, err := undo.Take(1); err == nil {
+		t.Error("Take() on an empty buffer should fail")
+	}
+
+	for _, text := range []string{"a", "b", "c"} {
+		_ = undo.Push(text)
+	}
+
+	entry, err := undo.Take(2)
+	if err != nil {
+		t.Fatalf("Take(2) error = %v", err)
+	}
+	if entry.Text != "b" {
+		t.Errorf("Take(2) = %q, want %q", entry.Text, "b")
+	}
+
+	entry, err = undo.Take(1)
+	if err != nil || entry.Text != "c" {
+		t.Errorf("Take(1) = %q, %v, want %q", entry.Text, err, "c")
+	}
+	if _, err := undo.Take(2); err == nil {
+		t.Error("Take() past the end should fail")
+	}
+}
@@ -0,0 +1,156 @@
+package clipboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultUndoSize is how many previous clipboard contents are kept
+	DefaultUndoSize = 10
+
+	undoFileName = "clipboard-undo.json"
+)
+
+// Saved is clipboard content the tool overwrote
+type Saved struct {
+	Time time.Time `json:"time"`
+	Text string    `json:"text"`
+}
+
+// Undo is a ring buffer of overwritten clipboard contents kept in a JSON
+// file. Once full, the oldest contents are dropped. A nil *Undo keeps
+// nothing.
+type Undo struct {
+	path string
+	size int
+	mu   sync.Mutex
+
+	// now is replaced in tests
+	now func() time.Time
+}
+
+// NewUndo returns an Undo buffer in dir holding up to size entries
+func NewUndo(dir string, size int) *Undo {
+	if size <= 0 {
+		size = DefaultUndoSize
+	}
+	return &Undo{
+		path: filepath.Join(dir, undoFileName),
+		size: size,
+		now:  time.Now,
+	}
+}
+
+// Push saves text as the most recent contents
+func (u *Undo) Push(text string) error {
+	if u == nil {
+		return nil
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	saved, err := u.read()
+	if err != nil {
+		return err
+	}
+	if len(saved) > 0 && saved[0].Text == text {
+		return nil
+	}
+
+	saved = append([]Saved{{Time: u.now().UTC(), Text: text}}, saved...)
+	if len(saved) > u.size {
+		saved = saved[:u.size]
+	}
+	return u.write(saved)
+}
+
+// List returns the saved contents, most recent first
+func (u *Undo) List() ([]Saved, error) {
+	if u == nil {
+		return nil, nil
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.read()
+}
+
+// Take removes and returns entry n, where 1 is the most recent
+func (u *Undo) Take(n int) (Saved, error) {
+	if u == nil {
+		return Saved{}, fmt.Errorf("nothing to undo")
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	saved, err := u.read()
+	if err != nil {
+		return Saved{}, err
+	}
+	if len(saved) == 0 {
+		return Saved{}, fmt.Errorf("nothing to undo")
+	}
+	if n < 1 || n > len(saved) {
+		return Saved{}, fmt.Errorf("no saved clipboard %d (%d saved)", n, len(saved))
+	}
+
+	taken := saved[n-1]
+	saved = append(saved[:n-1], saved[n:]...)
+	if err := u.write(saved); err != nil {
+		return Saved{}, err
+	}
+	return taken, nil
+}
+
+func (u *Undo) read() ([]Saved, error) {
+	data, err := os.ReadFile(u.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var saved []Saved
+	if err := json.Unmarshal(data, &saved); err != nil {
+		// A damaged buffer only loses undo history; start over
+		return nil, nil
+	}
+	return saved, nil
+}
+
+// write replaces the buffer file atomically. Clipboard contents may be
+// sensitive, so the file is readable only by the user.
+func (u *Undo) write(saved []Saved) error {
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(u.path), 0700); err != nil {
+		return fmt.Errorf("failed to create undo directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(u.path), undoFileName+".*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), u.path)
+}
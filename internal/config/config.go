@@ -5,80 +5,116 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/erebusbat/markdown-tool/internal/cache"
+	"github.com/erebusbat/markdown-tool/internal/history"
 	"github.com/erebusbat/markdown-tool/pkg/types"
 	"github.com/spf13/viper"
 )
 
-// Load loads configuration from file or creates default config
-func Load(configFile string) (*types.Config, error) {
-	// Create a new Viper instance with custom key delimiter to handle domain names with dots
-	// Using "::" instead of "." prevents domain names like "companycam.slack.com"
-	// from being interpreted as nested YAML structures
-	v := viper.NewWithOptions(viper.KeyDelimiter("::"))
+// ProfileEnvVar names the environment variable that selects a profile
+const ProfileEnvVar = "MARKDOWN_TOOL_PROFILE"
+
+// Options controls how configuration is loaded
+type Options struct {
+	// File is an explicit config file; empty uses the default location
+	File string
+	// Profile selects a profile explicitly, overriding ProfileEnvVar and match rules
+	Profile string
+	// Dir is the working directory used for automatic profile rules
+	Dir string
+	// Isolated ignores the environment: MDTOOL_* overrides, ProfileEnvVar
+	// and automatic profile rules, so only the files and Profile count
+	Isolated bool
+}
 
-	if configFile != "" {
-		v.SetConfigFile(configFile)
-	} else {
-		// Set default config path
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return nil, err
-		}
+// DefaultPath returns the user config file location, honouring
+// $XDG_CONFIG_HOME
+func DefaultPath() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "markdown-tool", "config.yaml"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "markdown-tool", "config.yaml"), nil
+}
 
-		configDir := filepath.Join(home, ".config", "markdown-tool")
-		configPath := filepath.Join(configDir, "config.yaml")
+// StateDir returns the directory for small bits of state, such as which
+// hints have been shown, honouring $XDG_STATE_HOME
+func StateDir() (string, error) {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "markdown-tool"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "state", "markdown-tool"), nil
+}
 
-		// Create config directory if it doesn't exist
-		if err := os.MkdirAll(configDir, 0755); err != nil {
-			return nil, fmt.Errorf("failed to create config directory: %w", err)
-		}
+// Path returns the config file that commands such as init and edit operate
+// on: configFile if set, otherwise the user config file
+func Path(configFile string) (string, error) {
+	if configFile != "" {
+		return configFile, nil
+	}
+	return DefaultPath()
+}
 
-		// Create default config if it doesn't exist
-		if _, err := os.Stat(configPath); os.IsNotExist(err) {
-			if err := createDefaultConfig(configPath); err != nil {
-				return nil, fmt.Errorf("failed to create default config: %w", err)
-			}
-		}
+// Load loads configuration from configFile, or from the default locations
+// when it is empty. Missing files are not an error: features that need
+// settings stay off until they are configured.
+func Load(configFile string) (*types.Config, error) {
+	cfg, _, err := LoadWithOptions(Options{File: configFile})
+	return cfg, err
+}
 
-		v.SetConfigFile(configPath)
+// LoadWithOptions merges the system, user and repo-local config files (or
+// just opts.File), applies the selected profile and then MDTOOL_*
+// environment overrides unless opts.Isolated is set. An explicit opts.File must exist. The files read
+// are returned alongside the config.
+func LoadWithOptions(opts Options) (*types.Config, []Source, error) {
+	settings, sources, err := loadLayers(opts)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	v.SetConfigType("yaml")
+	// Create a new Viper instance with custom key delimiter to handle domain names with dots
+	// Using "::" instead of "." prevents domain names like "companycam.slack.com"
+	// from being interpreted as nested YAML structures
+	v := viper.NewWithOptions(viper.KeyDelimiter("::"))
+	if err := v.MergeConfigMap(settings); err != nil {
+		return nil, nil, fmt.Errorf("failed to merge config: %w", err)
+	}
 
-	if err := v.ReadInConfig(); err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+	profile, err := applyProfile(v, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !opts.Isolated {
+		applyEnv(v)
 	}
 
 	var config types.Config
 	if err := v.Unmarshal(&config); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+		return nil, nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	config.Profile = profile
 
-	return &config, nil
-}
-
-// createDefaultConfig creates a default configuration file
-func createDefaultConfig(path string) error {
-	defaultConfig := `github:
-  default_org: "CompanyCam"
-  default_repo: "Company-Cam-API"
-  mappings:
-    "CompanyCam/Company-Cam-API": "CompanyCam/API"
-
-jira:
-  domain: "https://companycam.atlassian.net"
-  projects:
-    - "PLAT"
-    - "SPEED"
-
-jenkins:
-  domain: "https://jenkins.internal.upserve.com"
+	// Default the cache to the user's XDG cache directory
+	if config.Cache.Dir == "" {
+		if dir, err := cache.DefaultDir(); err == nil {
+			config.Cache.Dir = dir
+		}
+	}
 
-url:
-  domain_mappings:
-    companycam_slack_com: "slack"
-    youtube_com: "YouTube"
-`
+	// Default the history log to the user's XDG data directory
+	if config.History.Dir == "" {
+		if dir, err := history.DefaultDir(); err == nil {
+			config.History.Dir = dir
+		}
+	}
 
-	return os.WriteFile(path, []byte(defaultConfig), 0644)
+	return &config, sources, nil
 }
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/erebusbat/markdown-tool/internal/gitutil"
+)
+
+// InitOptions holds the values written by WriteInitial
+type InitOptions struct {
+	GitHubOrg    string
+	GitHubRepo   string
+	JIRADomain   string
+	JIRAProjects []string
+}
+
+// DetectInit suggests initial settings from the environment: the GitHub
+// remote of the repository containing dir (preferring "origin"), falling
+// back to github.user from the global git config for the org
+func DetectInit(dir string) InitOptions {
+	var opts InitOptions
+
+	var found *gitutil.Remote
+	for _, remote := range gitutil.FindRemotes(dir) {
+		if !strings.Contains(remote.Host, "github") {
+			continue
+		}
+		if found == nil || remote.Name == "origin" {
+			r := remote
+			found = &r
+		}
+	}
+	if found != nil {
+		opts.GitHubOrg = found.Org
+		opts.GitHubRepo = found.Repo
+		return opts
+	}
+
+	opts.GitHubOrg = gitutil.GitHubUser()
+	return opts
+}
+
+// RenderInitial returns a config file containing only the settings provided
+func RenderInitial(opts InitOptions) string {
+	var b strings.Builder
+	b.WriteString("# markdown-tool configuration\n")
+	b.WriteString("# Run `markdown-tool config validate` after editing.\n")
+
+	if opts.GitHubOrg != "" || opts.GitHubRepo != "" {
+		b.WriteString("\ngithub:\n")
+		if opts.GitHubOrg != "" {
+			fmt.Fprintf(&b, "  default_org: %q\n", opts.GitHubOrg)
+		}
+		if opts.GitHubRepo != "" {
+			fmt.Fprintf(&b, "  default_repo: %q\n", opts.GitHubRepo)
+		}
+		b.WriteString("  mappings: {}\n")
+	}
+
+	if opts.JIRADomain != "" || len(opts.JIRAProjects) > 0 {
+		b.WriteString("\njira:\n")
+		if opts.JIRADomain != "" {
+			fmt.Fprintf(&b, "  domain: %q\n", strings.TrimSuffix(opts.JIRADomain, "/"))
+		}
+		b.WriteString("  projects:\n")
+		for _, project := range opts.JIRAProjects {
+			fmt.Fprintf(&b, "    - %q\n", project)
+		}
+	}
+
+	return b.String()
+}
+
+// WriteInitial writes a new config file at path. It refuses to replace an
+// existing file unless force is set.
+func WriteInitial(path string, opts InitOptions, force bool) error {
+	if _, err := os.Stat(path); err == nil && !force {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", path)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	return os.WriteFile(path, []byte(RenderInitial(opts)), 0644)
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectInit(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg"))
+	if err := os.WriteFile(filepath.Join(home, ".gitconfig"), []byte("[github]\n\tuser = octocat\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := filepath.Join(home, "src", "api")
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	gitConfig := `[remote "fork"]
+	url = git@github.com:octocat/api.git
+[remote "origin"]
+	url = https://github.com/Acme/api.git
+[remote "mirror"]
+	url = git@gitlab.com:acme/api.git
+`
+	if err := os.WriteFile(filepath.Join(repo, ".git", "config"), []byte(gitConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		dir      string
+		wantOrg  string
+		wantRepo string
+	}{
+		{"GitHub origin remote", repo, "Acme", "api"},
+		{"Outside a repository falls back to github.user", home, "octocat", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DetectInit(tt.dir)
+			if opts.GitHubOrg != tt.wantOrg || opts.GitHubRepo != tt.wantRepo {
+				t.Errorf("DetectInit() = %s/%s, want %s/%s", opts.GitHubOrg, opts.GitHubRepo, tt.wantOrg, tt.wantRepo)
+			}
+			if opts.JIRADomain != "" || len(opts.JIRAProjects) > 0 {
+				t.Errorf("DetectInit() JIRA = %q %v, want nothing guessed", opts.JIRADomain, opts.JIRAProjects)
+			}
+		})
+	}
+}
+
+func TestWriteInitial(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "config.yaml")
+	opts := InitOptions{
+		GitHubOrg:    "Acme",
+		GitHubRepo:   "api",
+		JIRADomain:   "https://acme.atlassian.net/",
+		JIRAProjects: []string{"ACME", "OPS"},
+	}
+
+	if err := WriteInitial(path, opts, false); err != nil {
+		t.Fatalf("WriteInitial() error = %v", err)
+	}
+	if err := ValidateFile(path); err != nil {
+		t.Errorf("generated config does not validate: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.GitHub.DefaultOrg != "Acme" || cfg.GitHub.DefaultRepo != "api" {
+		t.Errorf("GitHub = %+v, want Acme/api", cfg.GitHub)
+	}
+	if cfg.JIRA.Domain != "https://acme.atlassian.net" {
+		t.Errorf("JIRA.Domain = %q, want trailing slash trimmed", cfg.JIRA.Domain)
+	}
+	if len(cfg.JIRA.Projects) != 2 {
+		t.Errorf("JIRA.Projects = %v, want [ACME OPS]", cfg.JIRA.Projects)
+	}
+
+	if err := WriteInitial(path, opts, false); err == nil {
+		t.Error("WriteInitial() should refuse to overwrite an existing file")
+	}
+	if err := WriteInitial(path, InitOptions{}, true); err != nil {
+		t.Errorf("WriteInitial() with force error = %v", err)
+	}
+}
@@ -0,0 +1,492 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/erebusbat/markdown-tool/pkg/types"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// RepoFileName is the repo-local config file found by walking up from the
+	// working directory
+	RepoFileName = ".markdown-tool.yaml"
+
+	// EnvPrefix prefixes environment variables that override single settings,
+	// e.g. MDTOOL_JIRA_DOMAIN
+	EnvPrefix = "MDTOOL_"
+
+	// profilesKey holds named profiles, which are merged over the shared
+	// settings only when selected
+	profilesKey = "profiles"
+
+	// includeKey names the directive that pulls other files into a layer
+	includeKey = "include"
+
+	// appendSuffix marks a list key whose items are appended to the list
+	// from lower layers instead of replacing it, e.g. "projects+"
+	appendSuffix = "+"
+)
+
+// Source kinds, in the order they are applied
+const (
+	SourceSystem  = "system"
+	SourceUser    = "user"
+	SourceRepo    = "repo"
+	SourceFile    = "file"
+	SourceInclude = "include"
+)
+
+// systemPath is the machine-wide config file; a variable so tests can move it
+var systemPath = "/etc/markdown-tool/config.yaml"
+
+// Source is one config file that contributes to the loaded configuration
+type Source struct {
+	Kind string
+	Path string
+}
+
+// Sources returns the config files that LoadWithOptions would read, lowest
+// precedence first. Included files are listed before the file including them.
+func Sources(opts Options) ([]Source, error) {
+	_, sources, err := loadLayers(opts)
+	return sources, err
+}
+
+// discover returns the top-level config files for opts. An explicit file
+// replaces the system, user and repo-local files.
+func discover(opts Options) ([]Source, error) {
+	if opts.File != "" {
+		return []Source{{Kind: SourceFile, Path: opts.File}}, nil
+	}
+
+	sources := make([]Source, 0, 3)
+	if fileExists(systemPath) {
+		sources = append(sources, Source{Kind: SourceSystem, Path: systemPath})
+	}
+
+	userPath, err := DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	if fileExists(userPath) {
+		sources = append(sources, Source{Kind: SourceUser, Path: userPath})
+	}
+
+	dir := opts.Dir
+	if dir == "" {
+		if dir, err = os.Getwd(); err != nil {
+			return sources, nil
+		}
+	}
+	if repoPath := findRepoFile(dir); repoPath != "" && repoPath != userPath {
+		sources = append(sources, Source{Kind: SourceRepo, Path: repoPath})
+	}
+
+	return sources, nil
+}
+
+// findRepoFile walks up from dir looking for RepoFileName
+func findRepoFile(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		candidate := filepath.Join(dir, RepoFileName)
+		if fileExists(candidate) {
+			return candidate
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// loadLayers reads and merges every config file for opts
+func loadLayers(opts Options) (map[string]interface{}, []Source, error) {
+	top, err := discover(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	merged := make(map[string]interface{})
+	sources := make([]Source, 0, len(top))
+	for _, source := range top {
+		loaded, err := loadFile(source, make(map[string]bool), &sources)
+		if err != nil {
+			return nil, nil, err
+		}
+		mergeLayer(merged, loaded)
+	}
+	resolvePending(merged)
+
+	return merged, sources, nil
+}
+
+// loadFile reads one config file with its includes merged beneath it.
+// Each file read is recorded in sources; seen guards against include cycles.
+func loadFile(source Source, seen map[string]bool, sources *[]Source) (map[string]interface{}, error) {
+	abs, err := filepath.Abs(source.Path)
+	if err != nil {
+		return nil, err
+	}
+	if seen[abs] {
+		return nil, fmt.Errorf("config include cycle at %s", source.Path)
+	}
+	seen[abs] = true
+	defer delete(seen, abs)
+
+	layer, err := readLayer(source.Path)
+	if err != nil {
+		return nil, err
+	}
+	if source.Kind == SourceRepo {
+		restrictLayer(layer, repoKeys, "")
+	}
+
+	includes, err := includePaths(layer[includeKey], filepath.Dir(source.Path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", source.Path, err)
+	}
+	delete(layer, includeKey)
+
+	merged := make(map[string]interface{})
+	for _, include := range includes {
+		included, err := loadFile(Source{Kind: SourceInclude, Path: include}, seen, sources)
+		if err != nil {
+			return nil, err
+		}
+		mergeLayer(merged, included)
+	}
+
+	*sources = append(*sources, source)
+	mergeLayer(merged, layer)
+	return merged, nil
+}
+
+// keyTree lists the settings allowed below a key. A nil subtree allows
+// everything below it and anyKey matches every key at its level.
+type keyTree map[string]keyTree
+
+const anyKey = "*"
+
+// profileKeys are the settings a repo-local file may set at the top level
+// or in a profile
+var profileKeys = keyTree{
+	"github": {"default_org": nil, "default_repo": nil, "mappings": nil, "pr_details": nil},
+	"jira":   {"projects": nil, "exclude": nil, "any_project": nil},
+	"url":    {"domain_mappings": nil},
+}
+
+// repoKeys are the settings a repo-local file may set. A repository anyone
+// can clone is only trusted with how links are displayed, never with API
+// endpoints, credentials, network access, file locations or includes,
+// which could send tokens or traffic elsewhere.
+var repoKeys = func() keyTree {
+	keys := keyTree{
+		"parse_timeout": nil,
+		profilesKey: {anyKey: {
+			"match": nil,
+		}},
+	}
+	for key, subtree := range profileKeys {
+		keys[key] = subtree
+		keys[profilesKey][anyKey][key] = subtree
+	}
+	return keys
+}()
+
+// restrictLayer removes the keys of layer that allowed doesn't list and
+// returns their dotted paths, sorted
+func restrictLayer(layer map[string]interface{}, allowed keyTree, prefix string) []string {
+	removed := make([]string, 0)
+	for key, value := range layer {
+		subtree, ok := allowed[strings.TrimSuffix(key, appendSuffix)]
+		if !ok {
+			subtree, ok = allowed[anyKey]
+		}
+		if !ok {
+			removed = append(removed, prefix+strings.TrimSuffix(key, appendSuffix))
+			delete(layer, key)
+			continue
+		}
+		if nested, isMap := value.(map[string]interface{}); isMap && subtree != nil {
+			removed = append(removed, restrictLayer(nested, subtree, prefix+key+".")...)
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
+
+// RepoIgnoredKeys returns the settings in the file at path that are ignored
+// when it is loaded as a repo-local file
+func RepoIgnoredKeys(path string) ([]string, error) {
+	layer, err := readLayer(path)
+	if err != nil {
+		return nil, err
+	}
+	return restrictLayer(layer, repoKeys, ""), nil
+}
+
+// readLayer parses a YAML config file into a map with lowercased keys,
+// matching how viper treats keys
+func readLayer(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	if raw == nil {
+		return make(map[string]interface{}), nil
+	}
+
+	layer, ok := normalize(raw).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("failed to read config file %s: top level must be a mapping", path)
+	}
+	return layer, nil
+}
+
+// includePaths resolves an include directive, a path or list of paths,
+// relative to the including file's directory
+func includePaths(value interface{}, baseDir string) ([]string, error) {
+	var raw []interface{}
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		raw = []interface{}{v}
+	case []interface{}:
+		raw = v
+	default:
+		return nil, fmt.Errorf("%s must be a path or a list of paths", includeKey)
+	}
+
+	paths := make([]string, 0, len(raw))
+	for _, item := range raw {
+		p, ok := item.(string)
+		if !ok || p == "" {
+			return nil, fmt.Errorf("%s must be a path or a list of paths", includeKey)
+		}
+		p = expandHome(p)
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(baseDir, p)
+		}
+		paths = append(paths, p)
+	}
+	return paths, nil
+}
+
+// normalize converts decoded YAML into nested map[string]interface{} values
+// with lowercased keys
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[strings.ToLower(key)] = normalize(item)
+		}
+		return out
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[strings.ToLower(fmt.Sprint(key))] = normalize(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = normalize(item)
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+// mergeLayer merges src over dst. Maps are merged key by key; lists and
+// scalars replace what is in dst, except that a key ending in appendSuffix
+// appends its items to the list under the bare key. Appends inside profiles
+// are kept as written so they apply when the profile is selected.
+func mergeLayer(dst, src map[string]interface{}) {
+	mergeMaps(dst, src, true)
+}
+
+func mergeMaps(dst, src map[string]interface{}, resolveAppends bool) {
+	// Plain keys first so "projects+" appends to "projects" from the same file
+	keys := make([]string, 0, len(src))
+	for key := range src {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		iAppend, jAppend := strings.HasSuffix(keys[i], appendSuffix), strings.HasSuffix(keys[j], appendSuffix)
+		if iAppend != jAppend {
+			return jAppend
+		}
+		return keys[i] < keys[j]
+	})
+
+	for _, key := range keys {
+		value := src[key]
+
+		if base := strings.TrimSuffix(key, appendSuffix); resolveAppends && base != key {
+			if items, ok := value.([]interface{}); ok {
+				if existing, ok := dst[base].([]interface{}); ok {
+					dst[base] = appendUnique(existing, items)
+				} else {
+					// Nothing to append to yet; keep the items pending in case a
+					// lower layer supplies the list
+					pending, _ := dst[key].([]interface{})
+					dst[key] = appendUnique(pending, items)
+				}
+				continue
+			}
+			key = base
+		}
+
+		srcMap, srcIsMap := value.(map[string]interface{})
+		if !srcIsMap {
+			dst[key] = value
+			// A replacement discards appends pending from lower layers
+			delete(dst, key+appendSuffix)
+			continue
+		}
+
+		resolveChild := resolveAppends && key != profilesKey
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if !dstIsMap {
+			// Copy so later layers never modify an earlier file's map
+			dstMap = make(map[string]interface{}, len(srcMap))
+			dst[key] = dstMap
+		}
+		mergeMaps(dstMap, srcMap, resolveChild)
+	}
+}
+
+// resolvePending turns appends that found no list to extend into plain
+// lists. Profiles are left alone until one is applied.
+func resolvePending(m map[string]interface{}) {
+	for key, value := range m {
+		if base := strings.TrimSuffix(key, appendSuffix); base != key {
+			if _, ok := m[base]; !ok {
+				m[base] = value
+			}
+			delete(m, key)
+			continue
+		}
+		if child, ok := value.(map[string]interface{}); ok && key != profilesKey {
+			resolvePending(child)
+		}
+	}
+}
+
+// appendUnique appends items to list, skipping values already present
+func appendUnique(list, items []interface{}) []interface{} {
+	out := make([]interface{}, 0, len(list)+len(items))
+	present := make(map[string]bool, len(list)+len(items))
+	for _, item := range append(append([]interface{}{}, list...), items...) {
+		key := fmt.Sprint(item)
+		if present[key] {
+			continue
+		}
+		present[key] = true
+		out = append(out, item)
+	}
+	return out
+}
+
+// applyEnv overrides settings from EnvPrefix environment variables. List
+// values are comma separated; a leading "+" appends to the configured list.
+func applyEnv(v *viper.Viper) {
+	for _, setting := range envSettings() {
+		value, ok := os.LookupEnv(setting.env)
+		if !ok {
+			continue
+		}
+
+		if !setting.list {
+			v.Set(setting.key, value)
+			continue
+		}
+
+		if rest, appendItems := strings.CutPrefix(value, appendSuffix); appendItems {
+			v.Set(setting.key, append(v.GetStringSlice(setting.key), splitEnvList(rest)...))
+			continue
+		}
+		v.Set(setting.key, splitEnvList(value))
+	}
+}
+
+type envSetting struct {
+	env  string
+	key  string
+	list bool
+}
+
+// envSettings lists every scalar and list setting in types.Config with its
+// environment variable, e.g. MDTOOL_GITHUB_DEFAULT_ORG for github::default_org.
+// Maps, lists of tables and profiles can only be set in files.
+func envSettings() []envSetting {
+	settings := make([]envSetting, 0)
+
+	var walk func(t reflect.Type, path []string)
+	walk = func(t reflect.Type, path []string) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := field.Tag.Get("mapstructure")
+			if name == "" || name == "-" {
+				continue
+			}
+
+			fieldPath := append(append([]string{}, path...), name)
+			switch field.Type.Kind() {
+			case reflect.Map:
+				continue
+			case reflect.Struct:
+				walk(field.Type, fieldPath)
+				continue
+			case reflect.Slice:
+				if elem := field.Type.Elem().Kind(); elem == reflect.Struct || elem == reflect.Map {
+					continue
+				}
+			}
+
+			settings = append(settings, envSetting{
+				env:  EnvPrefix + strings.ToUpper(strings.Join(fieldPath, "_")),
+				key:  strings.Join(fieldPath, "::"),
+				list: field.Type.Kind() == reflect.Slice,
+			})
+		}
+	}
+	walk(reflect.TypeOf(types.Config{}), nil)
+
+	return settings
+}
+
+func splitEnvList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,292 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// layeredEnv points the system and user config files into a temp dir and
+// returns that dir along with a working directory inside a fake repo
+func layeredEnv(t *testing.T) (root, workDir string) {
+	t.Helper()
+	root = t.TempDir()
+
+	originalSystemPath := systemPath
+	systemPath = filepath.Join(root, "etc", "config.yaml")
+	t.Cleanup(func() { systemPath = originalSystemPath })
+
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "xdg"))
+	t.Setenv(ProfileEnvVar, "")
+
+	workDir = filepath.Join(root, "repo", "sub", "dir")
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return root, workDir
+}
+
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+}
+
+func TestLoadWithOptions_Layers(t *testing.T) {
+	root, workDir := layeredEnv(t)
+
+	writeConfig(t, filepath.Join(root, "etc", "config.yaml"), `jira:
+  domain: "https://system.atlassian.net"
+  projects: ["SYS"]
+parse_timeout: 1s
+`)
+	writeConfig(t, filepath.Join(root, "dotfiles", "markdown-tool.yaml"), `github:
+  default_org: "Team"
+  mappings:
+    "team/long-api": "Team/API"
+    "team/long-web": "Team/web"
+url:
+  domain_mappings:
+    team_slack_com: "slack"
+`)
+	writeConfig(t, filepath.Join(root, "xdg", "markdown-tool", "config.yaml"), `include: ../../dotfiles/markdown-tool.yaml
+github:
+  mappings:
+    "team/long-web": "Team/www"
+jira:
+  projects+: ["ME"]
+`)
+	writeConfig(t, filepath.Join(root, "repo", RepoFileName), `github:
+  default_repo: "long-api"
+jira:
+  projects+: ["REPO", "SYS"]
+url:
+  domain_mappings:
+    youtube_com: "YouTube"
+`)
+
+	cfg, _, err := LoadWithOptions(Options{Dir: workDir})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+
+	if cfg.JIRA.Domain != "https://system.atlassian.net" {
+		t.Errorf("JIRA.Domain = %q, want the system value", cfg.JIRA.Domain)
+	}
+	if want := []string{"SYS", "ME", "REPO"}; !reflect.DeepEqual(cfg.JIRA.Projects, want) {
+		t.Errorf("JIRA.Projects = %v, want %v", cfg.JIRA.Projects, want)
+	}
+	if cfg.GitHub.DefaultOrg != "Team" || cfg.GitHub.DefaultRepo != "long-api" {
+		t.Errorf("GitHub = %s/%s, want Team/long-api", cfg.GitHub.DefaultOrg, cfg.GitHub.DefaultRepo)
+	}
+	wantMappings := map[string]string{"team/long-api": "Team/API", "team/long-web": "Team/www"}
+	if !reflect.DeepEqual(cfg.GitHub.Mappings, wantMappings) {
+		t.Errorf("GitHub.Mappings = %v, want %v", cfg.GitHub.Mappings, wantMappings)
+	}
+	if len(cfg.URL.DomainMappings) != 2 {
+		t.Errorf("URL.DomainMappings = %v, want shared and repo entries merged", cfg.URL.DomainMappings)
+	}
+	if cfg.ParseTimeout != time.Second {
+		t.Errorf("ParseTimeout = %v, want 1s", cfg.ParseTimeout)
+	}
+
+	sources, err := Sources(Options{Dir: workDir})
+	if err != nil {
+		t.Fatalf("Sources() error = %v", err)
+	}
+	kinds := make([]string, 0, len(sources))
+	for _, source := range sources {
+		kinds = append(kinds, source.Kind)
+	}
+	if want := []string{SourceSystem, SourceInclude, SourceUser, SourceRepo}; !reflect.DeepEqual(kinds, want) {
+		t.Errorf("Sources() kinds = %v, want %v", kinds, want)
+	}
+}
+
+func TestLoadWithOptions_ExplicitFileSkipsDiscovery(t *testing.T) {
+	root, workDir := layeredEnv(t)
+
+	writeConfig(t, filepath.Join(root, "repo", RepoFileName), `jira:
+  domain: "https://repo.atlassian.net"
+`)
+	explicit := filepath.Join(root, "explicit.yaml")
+	writeConfig(t, explicit, `jira:
+  projects: ["ONLY"]
+`)
+
+	cfg, _, err := LoadWithOptions(Options{File: explicit, Dir: workDir})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if cfg.JIRA.Domain != "" {
+		t.Errorf("JIRA.Domain = %q, repo-local file should be ignored with an explicit file", cfg.JIRA.Domain)
+	}
+}
+
+func TestLoadWithOptions_EnvOverrides(t *testing.T) {
+	root, _ := layeredEnv(t)
+	path := filepath.Join(root, "config.yaml")
+	writeConfig(t, path, `github:
+  default_org: "FileOrg"
+jira:
+  projects: ["FILE"]
+network:
+  deny: ["a.example.com"]
+`)
+
+	t.Setenv("MDTOOL_GITHUB_DEFAULT_ORG", "EnvOrg")
+	t.Setenv("MDTOOL_JIRA_PROJECTS", "+ENV, MORE")
+	t.Setenv("MDTOOL_NETWORK_DENY", "b.example.com")
+	t.Setenv("MDTOOL_PARSE_TIMEOUT", "3s")
+	t.Setenv("MDTOOL_ENRICHMENT_ENABLED", "true")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.GitHub.DefaultOrg != "EnvOrg" {
+		t.Errorf("GitHub.DefaultOrg = %q, want EnvOrg", cfg.GitHub.DefaultOrg)
+	}
+	if want := []string{"FILE", "ENV", "MORE"}; !reflect.DeepEqual(cfg.JIRA.Projects, want) {
+		t.Errorf("JIRA.Projects = %v, want %v", cfg.JIRA.Projects, want)
+	}
+	if want := []string{"b.example.com"}; !reflect.DeepEqual(cfg.Network.Deny, want) {
+		t.Errorf("Network.Deny = %v, want %v", cfg.Network.Deny, want)
+	}
+	if cfg.ParseTimeout != 3*time.Second {
+		t.Errorf("ParseTimeout = %v, want 3s", cfg.ParseTimeout)
+	}
+	if !cfg.Enrichment.Enabled {
+		t.Error("Enrichment.Enabled = false, want true from env")
+	}
+}
+
+func TestLoadWithOptions_ProfileAppend(t *testing.T) {
+	root, _ := layeredEnv(t)
+	path := filepath.Join(root, "config.yaml")
+	writeConfig(t, path, `jira:
+  projects: ["BASE"]
+profiles:
+  client:
+    jira:
+      projects+: ["CLIENT"]
+`)
+
+	cfg, _, err := LoadWithOptions(Options{File: path, Profile: "client"})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if want := []string{"BASE", "CLIENT"}; !reflect.DeepEqual(cfg.JIRA.Projects, want) {
+		t.Errorf("JIRA.Projects = %v, want %v", cfg.JIRA.Projects, want)
+	}
+	if err := ValidateFile(path); err != nil {
+		t.Errorf("ValidateFile() error = %v, append keys should be accepted", err)
+	}
+}
+
+func TestLoadWithOptions_IncludeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name: "Cycle",
+			files: map[string]string{
+				"config.yaml": "include: a.yaml\n",
+				"a.yaml":      "include: config.yaml\n",
+			},
+			wantErr: "include cycle",
+		},
+		{
+			name: "Missing file",
+			files: map[string]string{
+				"config.yaml": "include: [missing.yaml]\n",
+			},
+			wantErr: "failed to read config file",
+		},
+		{
+			name: "Not a path",
+			files: map[string]string{
+				"config.yaml": "include:\n  file: a.yaml\n",
+			},
+			wantErr: "must be a path or a list of paths",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, _ := layeredEnv(t)
+			for name, content := range tt.files {
+				writeConfig(t, filepath.Join(root, name), content)
+			}
+
+			_, err := Load(filepath.Join(root, "config.yaml"))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadWithOptions_RepoLayerIsRestricted(t *testing.T) {
+	root, workDir := layeredEnv(t)
+
+	writeConfig(t, filepath.Join(root, "xdg", "markdown-tool", "config.yaml"), `jira:
+  domain: "https://me.atlassian.net"
+  discovery:
+    token: "secret"
+`)
+	repoFile := filepath.Join(root, "repo", RepoFileName)
+	writeConfig(t, repoFile, `include: /etc/passwd
+github:
+  default_org: "Repo"
+  api:
+    enabled: true
+    base_url: "https://attacker.example.com"
+jira:
+  domain: "https://attacker.example.com"
+  projects+: ["REPO"]
+network:
+  mode: on
+profiles:
+  work:
+    github:
+      hosts:
+        - host: github.com
+          api_url: "https://attacker.example.com"
+      mappings:
+        "repo/api": "API"
+`)
+
+	cfg, _, err := LoadWithOptions(Options{Dir: workDir})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if cfg.GitHub.DefaultOrg != "Repo" || !reflect.DeepEqual(cfg.JIRA.Projects, []string{"REPO"}) {
+		t.Errorf("display settings from the repo file were not applied: %+v", cfg)
+	}
+	if cfg.JIRA.Domain != "https://me.atlassian.net" || cfg.GitHub.API.BaseURL != "" || cfg.GitHub.API.Enabled || cfg.Network.Mode != "" {
+		t.Errorf("endpoint or network settings from the repo file were applied: %+v", cfg)
+	}
+	if hosts := cfg.Profiles["work"].GitHub.Hosts; len(hosts) != 0 {
+		t.Errorf("profile hosts from the repo file were applied: %+v", hosts)
+	}
+
+	ignored, err := RepoIgnoredKeys(repoFile)
+	if err != nil {
+		t.Fatalf("RepoIgnoredKeys() error = %v", err)
+	}
+	want := []string{"github.api", "include", "jira.domain", "network", "profiles.work.github.hosts"}
+	if !reflect.DeepEqual(ignored, want) {
+		t.Errorf("RepoIgnoredKeys() = %v, want %v", ignored, want)
+	}
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/erebusbat/markdown-tool/internal/gitutil"
+	"github.com/erebusbat/markdown-tool/pkg/types"
+	"github.com/spf13/viper"
+)
+
+// applyProfile selects a profile and merges its settings over the shared
+// ones in v. It returns the selected profile name, or "" if none applies.
+func applyProfile(v *viper.Viper, opts Options) (string, error) {
+	var profiles map[string]types.ProfileConfig
+	if err := v.UnmarshalKey(profilesKey, &profiles); err != nil {
+		return "", fmt.Errorf("failed to unmarshal profiles: %w", err)
+	}
+
+	name, err := selectProfile(profiles, opts)
+	if err != nil || name == "" {
+		return "", err
+	}
+
+	// Merge the raw profile map rather than the decoded struct so that only
+	// keys present in the profile override shared settings
+	overrides, ok := v.Get(profilesKey + "::" + name).(map[string]interface{})
+	if !ok {
+		return name, nil
+	}
+
+	layer := make(map[string]interface{}, len(overrides))
+	for key, value := range overrides {
+		if key != "match" {
+			layer[key] = value
+		}
+	}
+	settings := v.AllSettings()
+	mergeLayer(settings, layer)
+	resolvePending(settings)
+	if err := v.MergeConfigMap(settings); err != nil {
+		return "", fmt.Errorf("failed to apply profile %q: %w", name, err)
+	}
+
+	return name, nil
+}
+
+// selectProfile picks a profile by explicit option, then ProfileEnvVar, then
+// the first profile (in name order) whose match rules apply to opts.Dir. An
+// isolated load only uses the explicit option.
+func selectProfile(profiles map[string]types.ProfileConfig, opts Options) (string, error) {
+	explicit := opts.Profile
+	if explicit == "" && !opts.Isolated {
+		explicit = os.Getenv(ProfileEnvVar)
+	}
+	if explicit != "" {
+		// Viper lowercases map keys, so profile names are case-insensitive
+		name := strings.ToLower(explicit)
+		if _, ok := profiles[name]; !ok {
+			return "", fmt.Errorf("unknown profile %q", explicit)
+		}
+		return name, nil
+	}
+
+	if len(profiles) == 0 || opts.Isolated {
+		return "", nil
+	}
+
+	dir := opts.Dir
+	if dir == "" {
+		var err error
+		if dir, err = os.Getwd(); err != nil {
+			return "", nil
+		}
+	}
+
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var remotes []gitutil.Remote
+	remotesLoaded := false
+	for _, name := range names {
+		match := profiles[name].Match
+		if matchesPath(dir, match.Paths) {
+			return name, nil
+		}
+
+		if len(match.Remotes) > 0 {
+			if !remotesLoaded {
+				remotes = gitutil.FindRemotes(dir)
+				remotesLoaded = true
+			}
+			if matchesRemote(remotes, match.Remotes) {
+				return name, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// matchesPath reports whether dir is one of paths or inside one of them
+func matchesPath(dir string, paths []string) bool {
+	dir = filepath.Clean(dir)
+	for _, p := range paths {
+		p = filepath.Clean(expandHome(p))
+		if dir == p || strings.HasPrefix(dir, p+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesRemote reports whether any remote matches one of the patterns.
+// The number of segments in a pattern picks what it is matched against:
+// "org", "org/repo" or "host/org/repo".
+func matchesRemote(remotes []gitutil.Remote, patterns []string) bool {
+	for _, remote := range remotes {
+		candidates := map[int]string{
+			1: remote.Org,
+			2: remote.Org + "/" + remote.Repo,
+			3: remote.Host + "/" + remote.Org + "/" + remote.Repo,
+		}
+		for _, pattern := range patterns {
+			pattern = strings.ToLower(strings.Trim(pattern, "/"))
+			candidate, ok := candidates[strings.Count(pattern, "/")+1]
+			if !ok {
+				continue
+			}
+			if matched, _ := path.Match(pattern, strings.ToLower(candidate)); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// expandHome replaces a leading ~ with the user's home directory
+func expandHome(p string) string {
+	if p != "~" && !strings.HasPrefix(p, "~/") {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return p
+	}
+	return filepath.Join(home, strings.TrimPrefix(p, "~"))
+}
@@ -0,0 +1,222 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const profileTestConfig = `github:
+  default_org: "SharedOrg"
+  default_repo: "shared-repo"
+  mappings:
+    "sharedorg/shared-repo": "Shared/Repo"
+
+jira:
+  domain: "https://shared.atlassian.net"
+  projects: ["SHR"]
+
+profiles:
+  clienta:
+    match:
+      paths: ["%s"]
+    github:
+      default_org: "ClientA"
+    jira:
+      domain: "https://clienta.atlassian.net"
+      projects: ["CLA"]
+  personal:
+    match:
+      remotes: ["ErebusBat"]
+    github:
+      default_org: "ErebusBat"
+      default_repo: "markdown-tool"
+      mappings:
+        "erebusbat/markdown-tool": "me/mdt"
+`
+
+func writeProfileConfig(t *testing.T, clientDir string) string {
+	t.Helper()
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := []byte(fmt.Sprintf(profileTestConfig, filepath.ToSlash(clientDir)))
+	if err := os.WriteFile(configPath, content, 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+	return configPath
+}
+
+func TestLoadWithOptions_NoProfile(t *testing.T) {
+	t.Setenv(ProfileEnvVar, "")
+	configPath := writeProfileConfig(t, "/nonexistent/client-a")
+
+	cfg, _, err := LoadWithOptions(Options{File: configPath, Dir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+
+	if cfg.Profile != "" {
+		t.Errorf("Profile = %q, want none", cfg.Profile)
+	}
+	if cfg.GitHub.DefaultOrg != "SharedOrg" {
+		t.Errorf("GitHub.DefaultOrg = %q, want SharedOrg", cfg.GitHub.DefaultOrg)
+	}
+	if len(cfg.Profiles) != 2 {
+		t.Errorf("len(Profiles) = %d, want 2", len(cfg.Profiles))
+	}
+}
+
+func TestLoadWithOptions_ExplicitProfileInheritsShared(t *testing.T) {
+	t.Setenv(ProfileEnvVar, "")
+	configPath := writeProfileConfig(t, "/nonexistent/client-a")
+
+	cfg, _, err := LoadWithOptions(Options{File: configPath, Profile: "ClientA", Dir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+
+	if cfg.Profile != "clienta" {
+		t.Errorf("Profile = %q, want clienta", cfg.Profile)
+	}
+	// Overridden by the profile
+	if cfg.GitHub.DefaultOrg != "ClientA" {
+		t.Errorf("GitHub.DefaultOrg = %q, want ClientA", cfg.GitHub.DefaultOrg)
+	}
+	if cfg.JIRA.Domain != "https://clienta.atlassian.net" {
+		t.Errorf("JIRA.Domain = %q, want the client domain", cfg.JIRA.Domain)
+	}
+	if len(cfg.JIRA.Projects) != 1 || cfg.JIRA.Projects[0] != "CLA" {
+		t.Errorf("JIRA.Projects = %v, want [CLA]", cfg.JIRA.Projects)
+	}
+	// Inherited from the shared settings
+	if cfg.GitHub.DefaultRepo != "shared-repo" {
+		t.Errorf("GitHub.DefaultRepo = %q, want shared-repo", cfg.GitHub.DefaultRepo)
+	}
+	if cfg.GitHub.Mappings["sharedorg/shared-repo"] != "Shared/Repo" {
+		t.Errorf("GitHub.Mappings = %v, want the shared mapping", cfg.GitHub.Mappings)
+	}
+}
+
+func TestLoadWithOptions_ProfileMapsDeepMerge(t *testing.T) {
+	configPath := writeProfileConfig(t, "/nonexistent/client-a")
+
+	cfg, _, err := LoadWithOptions(Options{File: configPath, Profile: "personal", Dir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+
+	expected := map[string]string{
+		"sharedorg/shared-repo":   "Shared/Repo",
+		"erebusbat/markdown-tool": "me/mdt",
+	}
+	if len(cfg.GitHub.Mappings) != len(expected) {
+		t.Fatalf("GitHub.Mappings = %v, want %v", cfg.GitHub.Mappings, expected)
+	}
+	for key, value := range expected {
+		if cfg.GitHub.Mappings[key] != value {
+			t.Errorf("GitHub.Mappings[%q] = %q, want %q", key, cfg.GitHub.Mappings[key], value)
+		}
+	}
+}
+
+func TestLoadWithOptions_ProfileFromEnv(t *testing.T) {
+	t.Setenv(ProfileEnvVar, "personal")
+	configPath := writeProfileConfig(t, "/nonexistent/client-a")
+
+	cfg, _, err := LoadWithOptions(Options{File: configPath, Dir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if cfg.Profile != "personal" {
+		t.Errorf("Profile = %q, want personal", cfg.Profile)
+	}
+
+	// An explicit profile beats the environment
+	cfg, _, err = LoadWithOptions(Options{File: configPath, Profile: "clienta", Dir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if cfg.Profile != "clienta" {
+		t.Errorf("Profile = %q, want clienta", cfg.Profile)
+	}
+}
+
+func TestLoadWithOptions_Isolated(t *testing.T) {
+	t.Setenv(ProfileEnvVar, "personal")
+	t.Setenv("MDTOOL_GITHUB_DEFAULT_ORG", "EnvOrg")
+	clientDir := t.TempDir()
+	configPath := writeProfileConfig(t, clientDir)
+
+	cfg, _, err := LoadWithOptions(Options{File: configPath, Dir: clientDir, Isolated: true})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if cfg.Profile != "" {
+		t.Errorf("Profile = %q, want none from the environment or match rules", cfg.Profile)
+	}
+	if cfg.GitHub.DefaultOrg != "SharedOrg" {
+		t.Errorf("GitHub.DefaultOrg = %q, want SharedOrg without env overrides", cfg.GitHub.DefaultOrg)
+	}
+
+	// An explicit profile still applies
+	cfg, _, err = LoadWithOptions(Options{File: configPath, Profile: "clienta", Isolated: true})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if cfg.Profile != "clienta" {
+		t.Errorf("Profile = %q, want clienta", cfg.Profile)
+	}
+}
+
+func TestLoadWithOptions_UnknownProfile(t *testing.T) {
+	t.Setenv(ProfileEnvVar, "")
+	configPath := writeProfileConfig(t, "/nonexistent/client-a")
+
+	if _, _, err := LoadWithOptions(Options{File: configPath, Profile: "nope"}); err == nil {
+		t.Error("LoadWithOptions() with an unknown profile should fail")
+	}
+}
+
+func TestLoadWithOptions_ProfileFromPathRule(t *testing.T) {
+	t.Setenv(ProfileEnvVar, "")
+	clientDir := t.TempDir()
+	configPath := writeProfileConfig(t, clientDir)
+
+	workDir := filepath.Join(clientDir, "repos", "api")
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, _, err := LoadWithOptions(Options{File: configPath, Dir: workDir})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if cfg.Profile != "clienta" {
+		t.Errorf("Profile = %q, want clienta", cfg.Profile)
+	}
+}
+
+func TestLoadWithOptions_ProfileFromRemoteRule(t *testing.T) {
+	t.Setenv(ProfileEnvVar, "")
+	configPath := writeProfileConfig(t, "/nonexistent/client-a")
+
+	repo := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	gitConfig := "[remote \"origin\"]\n\turl = git@github.com:ErebusBat/markdown-tool.git\n"
+	if err := os.WriteFile(filepath.Join(repo, ".git", "config"), []byte(gitConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, _, err := LoadWithOptions(Options{File: configPath, Dir: repo})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if cfg.Profile != "personal" {
+		t.Errorf("Profile = %q, want personal", cfg.Profile)
+	}
+	if cfg.GitHub.DefaultOrg != "ErebusBat" {
+		t.Errorf("GitHub.DefaultOrg = %q, want ErebusBat", cfg.GitHub.DefaultOrg)
+	}
+}
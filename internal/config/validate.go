@@ -0,0 +1,271 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	githubpkg "github.com/erebusbat/markdown-tool/internal/github"
+	jirapkg "github.com/erebusbat/markdown-tool/internal/jira"
+	"github.com/erebusbat/markdown-tool/pkg/types"
+	"github.com/spf13/viper"
+)
+
+var (
+	jiraProjectRegex = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+	// jiraExcludeRegex matches a project or a key, e.g. SHA or UTF-8
+	jiraExcludeRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*(?:-\d+)?$`)
+)
+
+// ValidationError lists every problem found in a config file
+type ValidationError struct {
+	Path     string
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %d problem(s):\n  - %s", e.Path, len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// ValidateFile strictly decodes the config file at path, rejecting unknown
+// keys, and then checks the values of every section and profile. Included
+// files are not followed; validate each source on its own.
+func ValidateFile(path string) error {
+	layer, err := readLayer(path)
+	if err != nil {
+		return err
+	}
+
+	problems := make([]string, 0)
+	if _, err := includePaths(layer[includeKey], filepath.Dir(path)); err != nil {
+		problems = append(problems, err.Error())
+	}
+	delete(layer, includeKey)
+
+	// Resolve "key+" appends so they are checked like the plain key
+	settings := make(map[string]interface{}, len(layer))
+	mergeLayer(settings, layer)
+	resolvePending(settings)
+	if profiles, ok := settings[profilesKey].(map[string]interface{}); ok {
+		for name, profile := range profiles {
+			if overrides, ok := profile.(map[string]interface{}); ok {
+				resolved := make(map[string]interface{}, len(overrides))
+				mergeLayer(resolved, overrides)
+				resolvePending(resolved)
+				profiles[name] = resolved
+			}
+		}
+	}
+
+	v := viper.NewWithOptions(viper.KeyDelimiter("::"))
+	if err := v.MergeConfigMap(settings); err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var strict types.Config
+	if err := v.UnmarshalExact(&strict); err != nil {
+		problems = append(problems, decodeProblems(err)...)
+	}
+
+	// Check values even when there are unknown keys so every problem is
+	// reported in one pass
+	var cfg types.Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return &ValidationError{Path: path, Problems: problems}
+	}
+	problems = append(problems, Validate(&cfg)...)
+
+	if len(problems) > 0 {
+		return &ValidationError{Path: path, Problems: problems}
+	}
+	return nil
+}
+
+// decodeProblems splits a mapstructure decoding error into one problem per line
+func decodeProblems(err error) []string {
+	problems := make([]string, 0)
+	for _, line := range strings.Split(err.Error(), "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "*"))
+		if line == "" || strings.HasPrefix(line, "decoding failed") {
+			continue
+		}
+		problems = append(problems, line)
+	}
+	return problems
+}
+
+// Validate checks the values of a decoded configuration and returns a
+// description of each problem found
+func Validate(cfg *types.Config) []string {
+	problems := validateSections("", cfg.GitHub, cfg.JIRA, cfg.Jenkins, cfg.URL)
+
+	switch strings.ToLower(cfg.Network.Mode) {
+	case "", types.NetworkModeOff, types.NetworkModeAllowlist, types.NetworkModeOn:
+	default:
+		problems = append(problems, fmt.Sprintf("network.mode: %q must be one of off, allowlist, on", cfg.Network.Mode))
+	}
+	if cfg.Network.Proxy != "" {
+		if problem := checkURL("network.proxy", cfg.Network.Proxy); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		profile := cfg.Profiles[name]
+		problems = append(problems, validateSections("profiles."+name+".", profile.GitHub, profile.JIRA, profile.Jenkins, profile.URL)...)
+	}
+
+	return problems
+}
+
+func validateSections(prefix string, github types.GitHubConfig, jira types.JIRAConfig, jenkins types.JenkinsConfig, urls types.URLConfig) []string {
+	problems := make([]string, 0)
+
+	for _, key := range sortedKeys(github.Mappings) {
+		if err := githubpkg.ValidateMapping(key, github.Mappings[key]); err != nil {
+			problems = append(problems, fmt.Sprintf("%sgithub.mappings: %v", prefix, err))
+		}
+	}
+
+	if github.API.BaseURL != "" {
+		if problem := checkURL(prefix+"github.api.base_url", github.API.BaseURL); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+
+	seenHosts := make(map[string]bool, len(github.Hosts))
+	for i, host := range github.Hosts {
+		field := fmt.Sprintf("%sgithub.hosts[%d]", prefix, i)
+		name := strings.ToLower(strings.TrimSpace(host.Host))
+		switch {
+		case name == "":
+			problems = append(problems, fmt.Sprintf("%s.host: must be set", field))
+		case strings.ContainsAny(name, "/: "):
+			problems = append(problems, fmt.Sprintf("%s.host: %q must be a hostname, not a URL", field, host.Host))
+		case seenHosts[name]:
+			problems = append(problems, fmt.Sprintf("%s.host: %q is listed more than once", field, host.Host))
+		}
+		seenHosts[name] = true
+
+		if host.BaseURL != "" {
+			if problem := checkURL(field+".base_url", host.BaseURL); problem != "" {
+				problems = append(problems, problem)
+			}
+		}
+		if host.APIURL != "" {
+			if problem := checkURL(field+".api_url", host.APIURL); problem != "" {
+				problems = append(problems, problem)
+			}
+		}
+		for _, key := range sortedKeys(host.Mappings) {
+			if err := githubpkg.ValidateMapping(key, host.Mappings[key]); err != nil {
+				problems = append(problems, fmt.Sprintf("%s.mappings: %v", field, err))
+			}
+		}
+	}
+
+	if jira.Domain != "" {
+		if problem := checkURL(prefix+"jira.domain", jira.Domain); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+	if problem := checkJIRAStyle(prefix+"jira.style", jira.Style); problem != "" {
+		problems = append(problems, problem)
+	}
+	for _, project := range jira.Projects {
+		if !jiraProjectRegex.MatchString(project) {
+			problems = append(problems, fmt.Sprintf("%sjira.projects: %q is not a valid project key", prefix, project))
+		}
+	}
+
+	for _, entry := range jira.Exclude {
+		if !jiraExcludeRegex.MatchString(strings.TrimSpace(entry)) {
+			problems = append(problems, fmt.Sprintf("%sjira.exclude: %q is not a project or issue key", prefix, entry))
+		}
+	}
+	if jira.Discovery.Enabled && jira.Domain == "" && len(jira.Instances) == 0 {
+		problems = append(problems, fmt.Sprintf("%sjira.discovery.enabled: needs jira.domain or jira.instances", prefix))
+	}
+	if jira.AnyProject && jira.Domain == "" && len(jira.Instances) == 0 {
+		problems = append(problems, fmt.Sprintf("%sjira.any_project: needs jira.domain or jira.instances", prefix))
+	}
+
+	// A project may only link to one site
+	seenProjects := make(map[string]bool, len(jira.Projects))
+	for _, project := range jira.Projects {
+		seenProjects[project] = true
+	}
+	for i, instance := range jira.Instances {
+		field := fmt.Sprintf("%sjira.instances[%d]", prefix, i)
+		if strings.TrimSpace(instance.Domain) == "" {
+			problems = append(problems, fmt.Sprintf("%s.domain: must be set", field))
+		} else if problem := checkURL(field+".domain", instance.Domain); problem != "" {
+			problems = append(problems, problem)
+		}
+		if problem := checkJIRAStyle(field+".style", instance.Style); problem != "" {
+			problems = append(problems, problem)
+		}
+		for _, project := range instance.Projects {
+			switch {
+			case !jiraProjectRegex.MatchString(project):
+				problems = append(problems, fmt.Sprintf("%s.projects: %q is not a valid project key", field, project))
+			case seenProjects[project]:
+				problems = append(problems, fmt.Sprintf("%s.projects: %q is already listed for another instance", field, project))
+			}
+			seenProjects[project] = true
+		}
+	}
+
+	if jenkins.Domain != "" {
+		if problem := checkURL(prefix+"jenkins.domain", jenkins.Domain); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+
+	for _, key := range sortedKeys(urls.DomainMappings) {
+		if strings.Contains(key, ".") {
+			problems = append(problems, fmt.Sprintf("%surl.domain_mappings: key %q must use underscores instead of dots", prefix, key))
+		}
+		if strings.TrimSpace(urls.DomainMappings[key]) == "" {
+			problems = append(problems, fmt.Sprintf("%surl.domain_mappings: %q maps to an empty name", prefix, key))
+		}
+	}
+
+	return problems
+}
+
+// checkURL returns a problem description unless value is an absolute http(s) URL
+func checkURL(field, value string) string {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Sprintf("%s: %q must be an absolute http(s) URL", field, value)
+	}
+	return ""
+}
+
+// checkJIRAStyle reports a JIRA URL style other than cloud or server; empty
+// means detected from the domain
+func checkJIRAStyle(field, value string) string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", jirapkg.StyleCloud, jirapkg.StyleServer:
+		return ""
+	}
+	return fmt.Sprintf("%s: %q must be %q or %q", field, value, jirapkg.StyleCloud, jirapkg.StyleServer)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
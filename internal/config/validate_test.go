@@ -0,0 +1,211 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		problems []string
+	}{
+		{
+			name: "Valid config",
+			content: `github:
+  default_org: "Acme"
+  mappings:
+    "acme/long-name": "Acme/short"
+jira:
+  domain: "https://acme.atlassian.net"
+  projects: ["ACME", "OPS2"]
+url:
+  domain_mappings:
+    acme_slack_com: "slack"
+network:
+  mode: allowlist
+  allow: ["www.youtube.com"]
+`,
+		},
+		{
+			name: "Misspelled key is rejected",
+			content: `jira:
+  domian: "https://acme.atlassian.net"
+`,
+			problems: []string{"'jira' has invalid keys: domian"},
+		},
+		{
+			name: "Unknown top-level section",
+			content: `gitub:
+  default_org: "Acme"
+`,
+			problems: []string{"invalid keys: gitub"},
+		},
+		{
+			name: "Domains must be URLs",
+			content: `jira:
+  domain: "acme.atlassian.net"
+jenkins:
+  domain: "ftp://jenkins.acme.com"
+`,
+			problems: []string{"jira.domain", "jenkins.domain"},
+		},
+		{
+			name: "Malformed mappings",
+			content: `github:
+  mappings:
+    "acme/api/extra": "Short"
+    "acme/api": ""
+    "acme/api-(.*": "API"
+    "acme/*": "A/*/*"
+    "acme": "A/B"
+url:
+  domain_mappings:
+    "acme.slack.com": "slack"
+`,
+			problems: []string{
+				`key "acme/api/extra" must be in org/repo form`,
+				`"acme/api" maps to an empty name`,
+				`key "acme/api-(.*" is not a valid regular expression`,
+				`"A/*/*" uses more "*" than "acme/*"`,
+				`org alias "acme" must map to an org name`,
+				"must use underscores",
+			},
+		},
+		{
+			name: "Mapping patterns",
+			content: `github:
+  mappings:
+    "acme/api": "API"
+    "acme": "A"
+    "acme/*": "A/*"
+    "acme/acme-(.*)": "A/$1"
+`,
+		},
+		{
+			name: "Valid GitHub hosts",
+			content: `github:
+  hosts:
+    - host: github.acme.com
+      base_url: "https://github.acme.com"
+      default_org: "Platform"
+      mappings:
+        "platform/api": "API"
+    - host: www.github.com
+`,
+		},
+		{
+			name: "Malformed GitHub hosts",
+			content: `github:
+  hosts:
+    - host: "https://github.acme.com"
+    - base_url: "github.acme.com"
+      mappings:
+        "platform/api/v2": "API"
+`,
+			problems: []string{"github.hosts[0].host: \"https://github.acme.com\" must be a hostname", "github.hosts[1].host: must be set", "github.hosts[1].base_url", `github.hosts[1].mappings: key "platform/api/v2"`},
+		},
+		{
+			name: "Invalid project key",
+			content: `jira:
+  projects: ["plat"]
+`,
+			problems: []string{`"plat" is not a valid project key`},
+		},
+		{
+			name: "Any project mode",
+			content: `jira:
+  domain: "https://example.atlassian.net"
+  any_project: true
+  exclude: ["UTF-8", "SHA"]
+  discovery:
+    enabled: true
+    user: "me@example.com"
+    token: "secret"
+`,
+		},
+		{
+			name: "Malformed any project mode",
+			content: `jira:
+  any_project: true
+  exclude: ["not a key"]
+  discovery:
+    enabled: true
+`,
+			problems: []string{`jira.exclude: "not a key"`, "jira.discovery.enabled: needs jira.domain", "jira.any_project: needs jira.domain"},
+		},
+		{
+			name: "Valid JIRA instances",
+			content: `jira:
+  domain: "https://example.atlassian.net"
+  projects: ["PLAT"]
+  instances:
+    - domain: "https://jira.client.com/jira"
+      projects: ["CLI", "OPS2"]
+      prefix: "Client"
+      style: server
+`,
+		},
+		{
+			name: "Malformed JIRA instances",
+			content: `jira:
+  domain: "https://jira.example.com"
+  style: atlassian
+  projects: ["PLAT"]
+  instances:
+    - projects: ["PLAT", "ops"]
+      style: hosted
+`,
+			problems: []string{`jira.style: "atlassian"`, "jira.instances[0].domain: must be set", `jira.instances[0].style: "hosted"`, `jira.instances[0].projects: "PLAT" is already listed`, `jira.instances[0].projects: "ops" is not a valid project key`},
+		},
+		{
+			name: "Invalid network mode",
+			content: `network:
+  mode: sometimes
+`,
+			problems: []string{"network.mode"},
+		},
+		{
+			name: "Problems inside profiles are reported",
+			content: `profiles:
+  client:
+    jira:
+      domain: "not a url"
+      projcts: ["X"]
+`,
+			problems: []string{"projcts", "profiles.client.jira.domain"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to create test config file: %v", err)
+			}
+
+			err := ValidateFile(path)
+			if len(tt.problems) == 0 {
+				if err != nil {
+					t.Errorf("ValidateFile() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("ValidateFile() error = %v, want *ValidationError", err)
+			}
+			joined := strings.Join(validationErr.Problems, "\n")
+			for _, problem := range tt.problems {
+				if !strings.Contains(joined, problem) {
+					t.Errorf("problems %q missing %q", validationErr.Problems, problem)
+				}
+			}
+		})
+	}
+}
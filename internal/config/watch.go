@@ -0,0 +1,253 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/erebusbat/markdown-tool/pkg/types"
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDelay collapses the burst of events editors produce for one save
+const reloadDelay = 100 * time.Millisecond
+
+// WatchStatus describes the reload history of a Watcher
+type WatchStatus struct {
+	// Reloads counts successful loads after the initial one
+	Reloads int `json:"reloads"`
+	// LastReload is when the current configuration was loaded
+	LastReload time.Time `json:"last_reload"`
+	// LastError is the most recent failed reload, cleared by a good one
+	LastError string `json:"last_error,omitempty"`
+	// Profile is the profile of the current configuration
+	Profile string `json:"profile,omitempty"`
+	// Sources are the files the current configuration was read from
+	Sources []string `json:"sources"`
+}
+
+// Watcher reloads configuration whenever one of its files changes. Each good
+// configuration is handed to apply; an invalid edit is logged and the
+// previous configuration stays in effect.
+type Watcher struct {
+	opts  Options
+	apply func(*types.Config) error
+
+	// reloadMu serializes reloads; mu guards status and dirs
+	reloadMu sync.Mutex
+	mu       sync.Mutex
+	status   WatchStatus
+	loaded   bool
+	dirs     map[string]bool
+}
+
+// NewWatcher returns a Watcher loading with opts and passing each valid
+// configuration to apply
+func NewWatcher(opts Options, apply func(*types.Config) error) *Watcher {
+	return &Watcher{
+		opts:  opts,
+		apply: apply,
+		dirs:  make(map[string]bool),
+	}
+}
+
+// Status returns a copy of the current reload status
+func (w *Watcher) Status() WatchStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	status := w.status
+	status.Sources = append([]string(nil), w.status.Sources...)
+	return status
+}
+
+// Reload validates and loads the configuration and hands it to apply. On
+// failure the error is recorded in Status and nothing is applied.
+func (w *Watcher) Reload() error {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	cfg, sources, err := w.load()
+	if err == nil {
+		err = w.apply(cfg)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err != nil {
+		w.status.LastError = err.Error()
+		return err
+	}
+
+	if w.loaded {
+		w.status.Reloads++
+	}
+	w.loaded = true
+	w.status.LastReload = time.Now()
+	w.status.LastError = ""
+	w.status.Profile = cfg.Profile
+	w.status.Sources = make([]string, 0, len(sources))
+	for _, source := range sources {
+		w.status.Sources = append(w.status.Sources, source.Path)
+	}
+	return nil
+}
+
+// load reads the configuration, rejecting it if any source is invalid
+func (w *Watcher) load() (*types.Config, []Source, error) {
+	sources, err := Sources(w.opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	problems := make([]error, 0)
+	for _, source := range sources {
+		if err := ValidateFile(source.Path); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	if len(problems) > 0 {
+		return nil, nil, errors.Join(problems...)
+	}
+
+	cfg, _, err := LoadWithOptions(w.opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, sources, nil
+}
+
+// Run loads the configuration, unless Reload already has, and then reloads
+// it on every change until ctx is done. It fails only if the initial load or
+// the file watcher fails.
+func (w *Watcher) Run(ctx context.Context) error {
+	w.mu.Lock()
+	loaded := w.loaded
+	w.mu.Unlock()
+	if !loaded {
+		if err := w.Reload(); err != nil {
+			return err
+		}
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to watch config: %w", err)
+	}
+	defer func() {
+		_ = watcher.Close()
+	}()
+	w.watchDirs(watcher)
+
+	// Editors often replace the file rather than write it, so directories
+	// are watched and events filtered by name
+	var timer *time.Timer
+	var pending <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !w.relevant(event.Name) {
+				continue
+			}
+			if timer == nil {
+				timer = time.NewTimer(reloadDelay)
+			} else {
+				timer.Reset(reloadDelay)
+			}
+			pending = timer.C
+
+		case <-pending:
+			pending = nil
+			if err := w.Reload(); err != nil {
+				log.Printf("config: reload failed, keeping previous configuration: %v", err)
+			} else {
+				log.Printf("config: reloaded (%d)", w.Status().Reloads)
+			}
+			// Includes may have been added or removed
+			w.watchDirs(watcher)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Printf("config: watch error: %v", err)
+		}
+	}
+}
+
+// watchDirs watches every directory a config file is, or could appear, in
+func (w *Watcher) watchDirs(watcher *fsnotify.Watcher) {
+	dirs := make([]string, 0)
+	for _, path := range w.candidates() {
+		dirs = append(dirs, filepath.Dir(absPath(path)))
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, dir := range dirs {
+		if w.dirs[dir] {
+			continue
+		}
+		if err := watcher.Add(dir); err == nil {
+			w.dirs[dir] = true
+		}
+	}
+}
+
+// relevant reports whether a change to name could change the configuration
+func (w *Watcher) relevant(name string) bool {
+	name = absPath(name)
+	for _, path := range w.candidates() {
+		if absPath(path) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// candidates lists the current config files plus the places a new one would
+// be picked up from
+func (w *Watcher) candidates() []string {
+	w.mu.Lock()
+	paths := append([]string(nil), w.status.Sources...)
+	w.mu.Unlock()
+
+	if w.opts.File != "" {
+		return append(paths, w.opts.File)
+	}
+
+	paths = append(paths, systemPath)
+	if userPath, err := DefaultPath(); err == nil {
+		paths = append(paths, userPath)
+	}
+	dir := w.opts.Dir
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	if dir != "" {
+		paths = append(paths, filepath.Join(dir, RepoFileName))
+	}
+	return paths
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
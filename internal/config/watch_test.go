@@ -0,0 +1,111 @@
+package config
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+func TestWatcher_Reload(t *testing.T) {
+	root, _ := layeredEnv(t)
+	path := filepath.Join(root, "config.yaml")
+	writeConfig(t, path, `jira:
+  domain: "https://first.atlassian.net"
+`)
+
+	var mu sync.Mutex
+	var current *types.Config
+	watcher := NewWatcher(Options{File: path}, func(cfg *types.Config) error {
+		mu.Lock()
+		defer mu.Unlock()
+		current = cfg
+		return nil
+	})
+	domain := func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		if current == nil {
+			return ""
+		}
+		return current.JIRA.Domain
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- watcher.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	})
+
+	waitFor(t, "initial load", func() bool { return domain() == "https://first.atlassian.net" })
+	if status := watcher.Status(); status.Reloads != 0 || len(status.Sources) != 1 {
+		t.Errorf("Status() after initial load = %+v, want 0 reloads and 1 source", status)
+	}
+
+	// A valid edit is picked up
+	writeConfig(t, path, `jira:
+  domain: "https://second.atlassian.net"
+`)
+	waitFor(t, "reload", func() bool { return domain() == "https://second.atlassian.net" })
+	if status := watcher.Status(); status.Reloads != 1 || status.LastError != "" {
+		t.Errorf("Status() after reload = %+v, want 1 reload and no error", status)
+	}
+
+	// An invalid edit keeps the previous configuration
+	writeConfig(t, path, `jira:
+  domian: "https://third.atlassian.net"
+`)
+	waitFor(t, "failed reload", func() bool { return watcher.Status().LastError != "" })
+	if got := domain(); got != "https://second.atlassian.net" {
+		t.Errorf("domain after invalid edit = %q, want previous configuration kept", got)
+	}
+	if status := watcher.Status(); status.Reloads != 1 {
+		t.Errorf("Status().Reloads = %d after a failed reload, want 1", status.Reloads)
+	}
+
+	// Fixing the file clears the error
+	writeConfig(t, path, `jira:
+  domain: "https://fourth.atlassian.net"
+`)
+	waitFor(t, "recovery", func() bool { return domain() == "https://fourth.atlassian.net" })
+	if status := watcher.Status(); status.Reloads != 2 || status.LastError != "" {
+		t.Errorf("Status() after recovery = %+v, want 2 reloads and no error", status)
+	}
+}
+
+func TestWatcher_InitialLoadFails(t *testing.T) {
+	root, _ := layeredEnv(t)
+	path := filepath.Join(root, "config.yaml")
+	writeConfig(t, path, "gitub:\n  default_org: x\n")
+
+	applied := false
+	watcher := NewWatcher(Options{File: path}, func(*types.Config) error {
+		applied = true
+		return nil
+	})
+
+	if err := watcher.Run(context.Background()); err == nil {
+		t.Error("Run() with an invalid config should fail")
+	}
+	if applied {
+		t.Error("invalid configuration was applied")
+	}
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,87 @@
+package enricher
+
+import (
+	"context"
+	"time"
+
+	"github.com/erebusbat/markdown-tool/internal/cache"
+	"github.com/erebusbat/markdown-tool/internal/fetch"
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+// DefaultTimeout is the enrichment deadline used when none is configured
+const DefaultTimeout = 2 * time.Second
+
+// GetEnrichers returns the enrichers enabled by cfg. Enrichment is opt-in and
+// is always disabled when the network is switched off.
+func GetEnrichers(cfg *types.Config) []types.Enricher {
+	if !Enabled(cfg) {
+		return nil
+	}
+
+	client := fetch.FromConfig(cfg, fetch.Options{
+		Timeout:      Timeout(cfg),
+		MaxBytes:     cfg.Enrichment.MaxBytes,
+		MaxRedirects: maxRedirects(cfg),
+	})
+	c := cache.FromConfig(cfg)
+
+	enrichers := make([]types.Enricher, 0, 2)
+	if cfg.GitHub.API.Enabled {
+		enrichers = append(enrichers, NewGitHubEnricher(cfg, c, client))
+	}
+	if cfg.Enrichment.Enabled {
+		enrichers = append(enrichers, NewTitleEnricher(c, NewHTMLTitleFetcher(client)))
+	}
+	return enrichers
+}
+
+// Enabled reports whether the enrichment stage should run: page titles
+// (enrichment.enabled) or GitHub lookups (github.api.enabled) are on and
+// the network is not off
+func Enabled(cfg *types.Config) bool {
+	return cfg != nil && (cfg.Enrichment.Enabled || cfg.GitHub.API.Enabled) && fetch.NewPolicy(cfg.Network).Mode != types.NetworkModeOff
+}
+
+// Timeout returns the configured enrichment deadline, falling back to DefaultTimeout
+func Timeout(cfg *types.Config) time.Duration {
+	if cfg == nil || cfg.Enrichment.Timeout <= 0 {
+		return DefaultTimeout
+	}
+	return cfg.Enrichment.Timeout
+}
+
+// EnrichAll runs every enricher over every parse context under a shared
+// deadline. Enrichment is best-effort: failures leave the context unchanged.
+func EnrichAll(ctx context.Context, enrichers []types.Enricher, contexts []*types.ParseContext, timeout time.Duration) {
+	if len(enrichers) == 0 || len(contexts) == 0 {
+		return
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for _, parsed := range contexts {
+		for _, e := range enrichers {
+			if ctx.Err() != nil {
+				return
+			}
+			_ = e.Enrich(ctx, parsed)
+		}
+	}
+}
+
+// maxRedirects maps enrichment.max_redirects onto fetch.Options, where 0
+// means the default rather than none
+func maxRedirects(cfg *types.Config) int {
+	switch {
+	case cfg.Enrichment.MaxRedirects == nil:
+		return 0
+	case *cfg.Enrichment.MaxRedirects <= 0:
+		return fetch.NoRedirects
+	}
+	return *cfg.Enrichment.MaxRedirects
+}
@@ -0,0 +1,154 @@
+package enricher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/erebusbat/markdown-tool/internal/cache"
+	"github.com/erebusbat/markdown-tool/internal/fetch"
+	"github.com/erebusbat/markdown-tool/internal/github"
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+// GitHub issue and pull request states set as "state" metadata
+const (
+	GitHubStateOpen   = "open"
+	GitHubStateClosed = "closed"
+	GitHubStateMerged = "merged"
+	GitHubStateDraft  = "draft"
+)
+
+// GitHubEnricher fills in the "title", "state" and "author" metadata of
+// GitHub issue and pull request links from the GitHub REST API
+type GitHubEnricher struct {
+	config *types.Config
+	hosts  *github.HostSet
+	cache  *cache.Cache
+	client *fetch.Client
+}
+
+func NewGitHubEnricher(cfg *types.Config, c *cache.Cache, client *fetch.Client) *GitHubEnricher {
+	return &GitHubEnricher{config: cfg, hosts: github.NewHostSet(cfg), cache: c, client: client}
+}
+
+func (e *GitHubEnricher) GetName() string {
+	return "GitHubEnricher"
+}
+
+// githubIssue is the part of the issues API response that is used. Pull
+// requests are issues too, with merge details under pull_request.
+type githubIssue struct {
+	Title string `json:"title"`
+	State string `json:"state"`
+	Draft bool   `json:"draft"`
+	User  struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	PullRequest *struct {
+		MergedAt *string `json:"merged_at"`
+	} `json:"pull_request"`
+}
+
+// githubDetails is what is cached for an issue or pull request
+type githubDetails struct {
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	Author string `json:"author,omitempty"`
+}
+
+func (e *GitHubEnricher) Enrich(ctx context.Context, parsed *types.ParseContext) error {
+	if parsed == nil || (parsed.DetectedType != types.ContentTypeGitHubURL && parsed.DetectedType != types.ContentTypeGitHubComment) {
+		return nil
+	}
+
+	field := func(key string) string {
+		value, _ := parsed.Metadata[key].(string)
+		return value
+	}
+	kind, number := field("type"), field("number")
+	if (kind != github.KindPull && kind != github.KindIssues) || number == "" {
+		return nil
+	}
+	org, repo := field("org"), field("repo")
+	if org == "" || repo == "" {
+		return nil
+	}
+
+	host, ok := e.hosts.Lookup(field("host"))
+	if !ok {
+		host = e.hosts.ForRepo(org, repo)
+	}
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/issues/%s", host.APIURL, url.PathEscape(org), url.PathEscape(repo), number)
+
+	value, err := e.cache.Fetch(ctx, cache.SourceGitHub, apiURL, func(ctx context.Context) (string, error) {
+		details, err := e.fetchDetails(ctx, apiURL, token(host))
+		if err != nil {
+			return "", err
+		}
+		data, err := json.Marshal(details)
+		return string(data), err
+	})
+	if err != nil {
+		return err
+	}
+
+	var details githubDetails
+	if err := json.Unmarshal([]byte(value), &details); err != nil {
+		return err
+	}
+	if details.Title != "" {
+		parsed.Metadata["title"] = cleanTitle(details.Title)
+	}
+	if details.State != "" {
+		parsed.Metadata["state"] = details.State
+	}
+	if details.Author != "" {
+		parsed.Metadata["author"] = details.Author
+	}
+	return nil
+}
+
+func (e *GitHubEnricher) fetchDetails(ctx context.Context, apiURL, token string) (githubDetails, error) {
+	headers := map[string]string{
+		"Accept":               "application/vnd.github+json",
+		"X-GitHub-Api-Version": "2022-11-28",
+	}
+	if token != "" {
+		headers["Authorization"] = "Bearer " + token
+	}
+
+	resp, err := e.client.GetWithHeaders(ctx, apiURL, headers)
+	if err != nil {
+		return githubDetails{}, err
+	}
+
+	var issue githubIssue
+	if err := json.Unmarshal(resp.Body, &issue); err != nil {
+		return githubDetails{}, err
+	}
+
+	state := issue.State
+	switch {
+	case issue.PullRequest != nil && issue.PullRequest.MergedAt != nil:
+		state = GitHubStateMerged
+	case state == GitHubStateOpen && issue.Draft:
+		state = GitHubStateDraft
+	}
+	return githubDetails{Title: issue.Title, State: state, Author: issue.User.Login}, nil
+}
+
+// token returns the API token for host. The GITHUB_TOKEN and GH_TOKEN
+// environment variables are only sent to api.github.com itself, never to a
+// base_url or api_url set in config.
+func token(host github.Host) string {
+	if host.Token != "" || host.Name != github.DefaultHost || host.APIURL != github.DefaultAPIURL {
+		return host.Token
+	}
+	if value := os.Getenv("GITHUB_TOKEN"); value != "" {
+		return value
+	}
+	return os.Getenv("GH_TOKEN")
+}
@@ -0,0 +1,157 @@
+package enricher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/erebusbat/markdown-tool/internal/cache"
+	"github.com/erebusbat/markdown-tool/internal/fetch"
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+// newGitHubAPI serves a few issues and pull requests in the shape of the
+// GitHub issues API
+func newGitHubAPI(t *testing.T, requests *int32, auth *string) *httptest.Server {
+	t.Helper()
+
+	responses := map[string]string{
+		"/repos/acme/api/issues/1": `{"title":"Fix  SSO\nlogging","state":"closed","user":{"login":"alice"},"pull_request":{"merged_at":"2026-01-01T00:00:00Z"}}`,
+		"/repos/acme/api/issues/2": `{"title":"Add caching","state":"open","draft":true,"user":{"login":"bob"},"pull_request":{"merged_at":null}}`,
+		"/repos/acme/api/issues/3": `{"title":"Crash on start","state":"closed","user":{"login":"carol"}}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		*auth = r.Header.Get("Authorization")
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGitHubEnricher_Enrich(t *testing.T) {
+	var requests int32
+	var auth string
+	server := newGitHubAPI(t, &requests, &auth)
+
+	cfg := &types.Config{GitHub: types.GitHubConfig{API: types.GitHubAPIConfig{Enabled: true, Token: "secret", BaseURL: server.URL}}}
+	e := NewGitHubEnricher(cfg, nil, fetch.New(fetch.Options{}))
+
+	tests := []struct {
+		name     string
+		metadata map[string]interface{}
+		title    interface{}
+		state    interface{}
+		author   interface{}
+	}{
+		{"Merged pull request", map[string]interface{}{"org": "acme", "repo": "api", "type": "pull", "number": "1"}, "Fix SSO logging", "merged", "alice"},
+		{"Draft pull request", map[string]interface{}{"org": "acme", "repo": "api", "type": "pull", "number": "2"}, "Add caching", "draft", "bob"},
+		{"Closed issue", map[string]interface{}{"org": "acme", "repo": "api", "type": "issues", "number": "3"}, "Crash on start", "closed", "carol"},
+		{"Missing issue", map[string]interface{}{"org": "acme", "repo": "api", "type": "issues", "number": "4"}, nil, nil, nil},
+		{"Not an issue", map[string]interface{}{"org": "acme", "repo": "api", "type": "blob", "path": "go.mod"}, nil, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed := &types.ParseContext{DetectedType: types.ContentTypeGitHubURL, Metadata: tt.metadata}
+			_ = e.Enrich(context.Background(), parsed)
+
+			if got := parsed.Metadata["title"]; got != tt.title {
+				t.Errorf("title = %v, want %v", got, tt.title)
+			}
+			if got := parsed.Metadata["state"]; got != tt.state {
+				t.Errorf("state = %v, want %v", got, tt.state)
+			}
+			if got := parsed.Metadata["author"]; got != tt.author {
+				t.Errorf("author = %v, want %v", got, tt.author)
+			}
+		})
+	}
+
+	if auth != "Bearer secret" {
+		t.Errorf("Authorization = %q, want the configured token", auth)
+	}
+}
+
+func TestGitHubEnricher_CacheAndPolicy(t *testing.T) {
+	var requests int32
+	var auth string
+	server := newGitHubAPI(t, &requests, &auth)
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+
+	cfg := &types.Config{GitHub: types.GitHubConfig{API: types.GitHubAPIConfig{Enabled: true, BaseURL: server.URL}}}
+	c := cache.New(t.TempDir(), cache.Options{})
+	metadata := func() map[string]interface{} {
+		return map[string]interface{}{"org": "acme", "repo": "api", "type": "pull", "number": "1"}
+	}
+
+	// Denied by policy: nothing fetched or cached
+	denied := fetch.New(fetch.Options{Policy: fetch.NewPolicy(types.NetworkConfig{Mode: types.NetworkModeAllowlist})})
+	parsed := &types.ParseContext{DetectedType: types.ContentTypeGitHubURL, Metadata: metadata()}
+	if err := NewGitHubEnricher(cfg, c, denied).Enrich(context.Background(), parsed); err == nil {
+		t.Error("Enrich() should fail when the API host is not allowed")
+	}
+	if requests != 0 {
+		t.Fatalf("denied lookup made %d requests", requests)
+	}
+
+	e := NewGitHubEnricher(cfg, c, fetch.New(fetch.Options{Timeout: time.Second}))
+	for i := 0; i < 2; i++ {
+		parsed := &types.ParseContext{DetectedType: types.ContentTypeGitHubComment, Metadata: metadata()}
+		if err := e.Enrich(context.Background(), parsed); err != nil {
+			t.Fatalf("Enrich() error = %v", err)
+		}
+		if parsed.Metadata["state"] != "merged" {
+			t.Errorf("state = %v, want merged", parsed.Metadata["state"])
+		}
+	}
+	if requests != 1 {
+		t.Errorf("API requests = %d, want 1 with the cache", requests)
+	}
+	if auth != "" {
+		t.Errorf("Authorization = %q, want none without a token", auth)
+	}
+}
+
+func TestGitHubEnricher_EnvTokenStaysOnGitHub(t *testing.T) {
+	var requests int32
+	var auth string
+	server := newGitHubAPI(t, &requests, &auth)
+	t.Setenv("GITHUB_TOKEN", "env-secret")
+	t.Setenv("GH_TOKEN", "env-secret")
+
+	tests := []struct {
+		name string
+		cfg  *types.Config
+	}{
+		{"base_url", &types.Config{GitHub: types.GitHubConfig{API: types.GitHubAPIConfig{Enabled: true, BaseURL: server.URL}}}},
+		{"github.com host entry", &types.Config{GitHub: types.GitHubConfig{
+			API:   types.GitHubAPIConfig{Enabled: true},
+			Hosts: []types.GitHubHostConfig{{Host: "github.com", APIURL: server.URL}},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth = ""
+			parsed := &types.ParseContext{DetectedType: types.ContentTypeGitHubURL, Metadata: map[string]interface{}{"org": "acme", "repo": "api", "type": "pull", "number": "1"}}
+			if err := NewGitHubEnricher(tt.cfg, nil, fetch.New(fetch.Options{})).Enrich(context.Background(), parsed); err != nil {
+				t.Fatalf("Enrich() error = %v", err)
+			}
+			if auth != "" {
+				t.Errorf("Authorization = %q, want the environment token kept from a configured API URL", auth)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package enricher
+
+import (
+	"context"
+	"encoding/json"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/erebusbat/markdown-tool/internal/fetch"
+)
+
+var (
+	linkTagRegex  = regexp.MustCompile(`(?is)<link\b[^>]*>`)
+	metaTagRegex  = regexp.MustCompile(`(?is)<meta\b[^>]*>`)
+	titleTagRegex = regexp.MustCompile(`(?is)<title\b[^>]*>(.*?)</title>`)
+	attrRegex     = regexp.MustCompile(`([a-zA-Z_:][-a-zA-Z0-9_:.]*)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+)
+
+// HTMLTitleFetcher fetches a page and extracts its title, preferring an
+// advertised oEmbed endpoint, then OpenGraph og:title, then <title>
+type HTMLTitleFetcher struct {
+	client *fetch.Client
+}
+
+func NewHTMLTitleFetcher(client *fetch.Client) *HTMLTitleFetcher {
+	return &HTMLTitleFetcher{client: client}
+}
+
+func (f *HTMLTitleFetcher) FetchTitle(ctx context.Context, pageURL string) (string, error) {
+	resp, err := f.client.Get(ctx, pageURL)
+	if err != nil {
+		return "", err
+	}
+
+	doc := resp.Text()
+
+	if href := discoverOEmbedURL(doc); href != "" {
+		if oembedURL, err := resp.URL.Parse(href); err == nil {
+			if title := f.fetchOEmbedTitle(ctx, oembedURL); title != "" {
+				return title, nil
+			}
+		}
+	}
+
+	if title := extractOpenGraphTitle(doc); title != "" {
+		return title, nil
+	}
+
+	return extractHTMLTitle(doc), nil
+}
+
+func (f *HTMLTitleFetcher) fetchOEmbedTitle(ctx context.Context, oembedURL *url.URL) string {
+	resp, err := f.client.Get(ctx, oembedURL.String())
+	if err != nil {
+		return ""
+	}
+
+	var result struct {
+		Title string `json:"title"`
+	}
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		return ""
+	}
+
+	return result.Title
+}
+
+// discoverOEmbedURL returns the href of a <link type="application/json+oembed"> tag
+func discoverOEmbedURL(doc string) string {
+	for _, tag := range linkTagRegex.FindAllString(doc, -1) {
+		attrs := parseAttributes(tag)
+		if strings.EqualFold(attrs["type"], "application/json+oembed") && attrs["href"] != "" {
+			return attrs["href"]
+		}
+	}
+	return ""
+}
+
+// extractOpenGraphTitle returns the content of <meta property="og:title">
+func extractOpenGraphTitle(doc string) string {
+	for _, tag := range metaTagRegex.FindAllString(doc, -1) {
+		attrs := parseAttributes(tag)
+		property := attrs["property"]
+		if property == "" {
+			property = attrs["name"]
+		}
+		if strings.EqualFold(property, "og:title") {
+			return strings.TrimSpace(attrs["content"])
+		}
+	}
+	return ""
+}
+
+// extractHTMLTitle returns the text of the first <title> element
+func extractHTMLTitle(doc string) string {
+	matches := titleTagRegex.FindStringSubmatch(doc)
+	if len(matches) < 2 {
+		return ""
+	}
+	return strings.TrimSpace(html.UnescapeString(matches[1]))
+}
+
+// parseAttributes extracts lower-cased attribute names and unescaped values from a tag
+func parseAttributes(tag string) map[string]string {
+	attrs := make(map[string]string)
+	for _, m := range attrRegex.FindAllStringSubmatch(tag, -1) {
+		value := m[2]
+		if value == "" {
+			value = m[3]
+		}
+		if value == "" {
+			value = m[4]
+		}
+		attrs[strings.ToLower(m[1])] = html.UnescapeString(value)
+	}
+	return attrs
+}
@@ -0,0 +1,91 @@
+package enricher
+
+import (
+	"context"
+	"strings"
+
+	"github.com/erebusbat/markdown-tool/internal/cache"
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+// TitleFetcher looks up a human readable title for a URL
+type TitleFetcher interface {
+	FetchTitle(ctx context.Context, pageURL string) (string, error)
+}
+
+// TitleEnricher fills in the "title" metadata for URL inputs that were
+// parsed without one, trying each fetcher in order until one succeeds
+type TitleEnricher struct {
+	cache    *cache.Cache
+	fetchers []TitleFetcher
+}
+
+func NewTitleEnricher(c *cache.Cache, fetchers ...TitleFetcher) *TitleEnricher {
+	return &TitleEnricher{cache: c, fetchers: fetchers}
+}
+
+func (e *TitleEnricher) GetName() string {
+	return "TitleEnricher"
+}
+
+func (e *TitleEnricher) Enrich(ctx context.Context, parsed *types.ParseContext) error {
+	if !e.needsTitle(parsed) {
+		return nil
+	}
+
+	pageURL := strings.TrimSpace(parsed.OriginalInput)
+
+	title, err := e.cache.Fetch(ctx, cache.SourceTitle, pageURL, func(ctx context.Context) (string, error) {
+		return e.fetchTitle(ctx, pageURL)
+	})
+	if title != "" {
+		parsed.Metadata["title"] = title
+	}
+	return err
+}
+
+// fetchTitle tries each fetcher in order until one returns a title
+func (e *TitleEnricher) fetchTitle(ctx context.Context, pageURL string) (string, error) {
+	var lastErr error
+	for _, f := range e.fetchers {
+		title, err := f.FetchTitle(ctx, pageURL)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if title = cleanTitle(title); title != "" {
+			return title, nil
+		}
+	}
+
+	return "", lastErr
+}
+
+func (e *TitleEnricher) needsTitle(parsed *types.ParseContext) bool {
+	if parsed == nil {
+		return false
+	}
+
+	// GitHub pages and git remotes are described by the link text;
+	// GitHubEnricher adds issue and pull request titles
+	switch parsed.DetectedType {
+	case types.ContentTypeGitHubURL, types.ContentTypeGitHubComment, types.ContentTypeGitRemote:
+		return false
+	}
+
+	input := strings.TrimSpace(parsed.OriginalInput)
+	if !strings.HasPrefix(input, "http://") && !strings.HasPrefix(input, "https://") {
+		return false
+	}
+
+	if parsed.Metadata == nil {
+		parsed.Metadata = make(map[string]interface{})
+	}
+	title, _ := parsed.Metadata["title"].(string)
+	return title == ""
+}
+
+// cleanTitle collapses runs of whitespace so titles fit on a single line
+func cleanTitle(title string) string {
+	return strings.Join(strings.Fields(title), " ")
+}
@@ -0,0 +1,180 @@
+package enricher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/erebusbat/markdown-tool/internal/fetch"
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+// newTestSite serves pages exercising each title source
+func newTestSite(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oembed-page", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><head>
+<link rel="alternate" type="application/json+oembed" href="/oembed.json?url=x">
+<meta property="og:title" content="OpenGraph Title">
+<title>HTML Title</title>
+</head></html>`))
+	})
+	mux.HandleFunc("/oembed.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"title":"oEmbed Title"}`))
+	})
+	mux.HandleFunc("/og-page", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<head><meta content='Tom &amp; Jerry' property='og:title'><title>HTML Title</title></head>`))
+	})
+	mux.HandleFunc("/title-page", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<head><TITLE>\n  Plain   Title\n</TITLE></head>"))
+	})
+	mux.HandleFunc("/latin1-page", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=iso-8859-1")
+		_, _ = w.Write([]byte("<title>Caf\xe9 Menu</title>"))
+	})
+	mux.HandleFunc("/no-title", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<p>nothing here</p>"))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTMLTitleFetcher_FetchTitle(t *testing.T) {
+	server := newTestSite(t)
+	fetcher := NewHTMLTitleFetcher(fetch.New(fetch.Options{}))
+
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{"oEmbed discovery wins", "/oembed-page", "oEmbed Title"},
+		{"OpenGraph before title", "/og-page", "Tom & Jerry"},
+		{"HTML title fallback", "/title-page", "Plain   Title"},
+		{"Charset decoding", "/latin1-page", "Café Menu"},
+		{"No title", "/no-title", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, err := fetcher.FetchTitle(context.Background(), server.URL+tt.path)
+			if err != nil {
+				t.Fatalf("FetchTitle() error = %v", err)
+			}
+			if title != tt.expected {
+				t.Errorf("FetchTitle() = %q, want %q", title, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTitleEnricher_Enrich(t *testing.T) {
+	server := newTestSite(t)
+	e := NewTitleEnricher(nil, NewHTMLTitleFetcher(fetch.New(fetch.Options{})))
+
+	tests := []struct {
+		name     string
+		parsed   *types.ParseContext
+		expected interface{}
+	}{
+		{
+			name: "Generic URL gets title",
+			parsed: &types.ParseContext{
+				OriginalInput: server.URL + "/title-page",
+				DetectedType:  types.ContentTypeURL,
+				Metadata:      map[string]interface{}{},
+			},
+			expected: "Plain Title",
+		},
+		{
+			name: "Existing title is kept",
+			parsed: &types.ParseContext{
+				OriginalInput: server.URL + "/title-page",
+				DetectedType:  types.ContentTypeNotionURL,
+				Metadata:      map[string]interface{}{"title": "From Slug"},
+			},
+			expected: "From Slug",
+		},
+		{
+			name: "Other types lacking a title are enriched",
+			parsed: &types.ParseContext{
+				OriginalInput: server.URL + "/og-page",
+				DetectedType:  types.ContentTypeYouTubeURL,
+				Metadata:      map[string]interface{}{},
+			},
+			expected: "Tom & Jerry",
+		},
+		{
+			name: "Non-URL input is ignored",
+			parsed: &types.ParseContext{
+				OriginalInput: "PLAT-123",
+				DetectedType:  types.ContentTypeJIRAKey,
+				Metadata:      map[string]interface{}{},
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = e.Enrich(context.Background(), tt.parsed)
+			if got := tt.parsed.Metadata["title"]; got != tt.expected {
+				t.Errorf("Metadata[title] = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetEnrichers(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      *types.Config
+		expected int
+	}{
+		{"Disabled by default", &types.Config{}, 0},
+		{"Network unset", &types.Config{Enrichment: types.EnrichmentConfig{Enabled: true}}, 0},
+		{"Enabled", &types.Config{Enrichment: types.EnrichmentConfig{Enabled: true}, Network: types.NetworkConfig{Mode: types.NetworkModeOn}}, 1},
+		{"Network off", &types.Config{Enrichment: types.EnrichmentConfig{Enabled: true}, Network: types.NetworkConfig{Mode: types.NetworkModeOff}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := len(GetEnrichers(tt.cfg)); got != tt.expected {
+				t.Errorf("len(GetEnrichers()) = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEnrichAll_RespectsDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	parsed := &types.ParseContext{
+		OriginalInput: server.URL,
+		DetectedType:  types.ContentTypeURL,
+		Metadata:      map[string]interface{}{},
+	}
+	enrichers := []types.Enricher{NewTitleEnricher(nil, NewHTMLTitleFetcher(fetch.New(fetch.Options{})))}
+
+	start := time.Now()
+	EnrichAll(context.Background(), enrichers, []*types.ParseContext{parsed}, 50*time.Millisecond)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("EnrichAll() took %v, want it bounded by the deadline", elapsed)
+	}
+	if _, ok := parsed.Metadata["title"]; ok {
+		t.Errorf("Metadata[title] should be unset after a timeout")
+	}
+}
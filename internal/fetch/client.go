@@ -0,0 +1,251 @@
+package fetch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/erebusbat/markdown-tool/pkg/types"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// Defaults applied when Options leaves a limit unset
+const (
+	DefaultTimeout      = 5 * time.Second
+	DefaultMaxBytes     = 1 << 20 // 1 MiB is plenty to find a <title>
+	DefaultMaxRedirects = 5
+	DefaultUserAgent    = "markdown-tool"
+)
+
+// NoRedirects as Options.MaxRedirects makes any redirect fail with
+// ErrTooManyRedirects instead of being followed
+const NoRedirects = -1
+
+// ErrTooManyRedirects is returned when a request exceeds Options.MaxRedirects
+var ErrTooManyRedirects = errors.New("too many redirects")
+
+// Options configures the limits applied to every request made by a Client
+type Options struct {
+	Timeout      time.Duration
+	MaxBytes     int64
+	MaxRedirects int
+	UserAgent    string
+
+	// Policy restricts which hosts may be contacted; nil allows all
+	Policy *Policy
+	// Proxy is an optional HTTP proxy URL; empty uses the environment
+	Proxy string
+	// Trace receives a line for every request and policy decision
+	Trace func(format string, args ...interface{})
+}
+
+// Client is a small HTTP client with conservative limits for fetching
+// third-party pages and APIs. Every outbound request in the tool goes
+// through a Client so the network policy is enforced in one place.
+type Client struct {
+	http    *http.Client
+	options Options
+}
+
+// Response holds a (possibly truncated) response body
+type Response struct {
+	URL         *url.URL
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	Truncated   bool
+}
+
+// New creates a Client, filling in defaults for any unset option
+func New(opts Options) *Client {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = DefaultMaxBytes
+	}
+	if opts.MaxRedirects == 0 {
+		opts.MaxRedirects = DefaultMaxRedirects
+	}
+	if opts.UserAgent == "" {
+		opts.UserAgent = DefaultUserAgent
+	}
+
+	maxRedirects := opts.MaxRedirects
+	if maxRedirects < 0 {
+		maxRedirects = 0
+	}
+	return &Client{
+		options: opts,
+		http: &http.Client{
+			Timeout:   opts.Timeout,
+			Transport: newPolicyTransport(opts),
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) > maxRedirects {
+					return ErrTooManyRedirects
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// FromConfig creates a Client governed by the network section of cfg.
+// Limits in opts are kept; policy, proxy, user agent and tracing come from cfg.
+func FromConfig(cfg *types.Config, opts Options) *Client {
+	if cfg == nil {
+		return New(opts)
+	}
+
+	opts.Policy = NewPolicy(cfg.Network)
+	opts.Proxy = cfg.Network.Proxy
+	if cfg.Network.UserAgent != "" {
+		opts.UserAgent = cfg.Network.UserAgent
+	}
+	if cfg.Verbose {
+		opts.Trace = log.Printf
+	}
+	return New(opts)
+}
+
+// Get fetches rawURL and reads at most Options.MaxBytes of the body.
+// Non-2xx responses are returned as errors.
+func (c *Client) Get(ctx context.Context, rawURL string) (*Response, error) {
+	return c.GetWithHeaders(ctx, rawURL, nil)
+}
+
+// GetWithHeaders is Get with extra request headers, e.g. for API
+// authentication. Go drops Authorization when redirected to another host.
+func (c *Client) GetWithHeaders(ctx context.Context, rawURL string, headers map[string]string) (*Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", c.options.UserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/json;q=0.9,*/*;q=0.8")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("GET %s: unexpected status %d", rawURL, resp.StatusCode)
+	}
+
+	// Read one byte past the limit so truncation can be detected
+	body, err := io.ReadAll(io.LimitReader(resp.Body, c.options.MaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+
+	truncated := int64(len(body)) > c.options.MaxBytes
+	if truncated {
+		body = body[:c.options.MaxBytes]
+	}
+
+	return &Response{
+		URL:         resp.Request.URL,
+		StatusCode:  resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        body,
+		Truncated:   truncated,
+	}, nil
+}
+
+var metaCharsetRegex = regexp.MustCompile(`(?i)<meta[^>]+charset\s*=\s*["']?([a-zA-Z0-9_:.-]+)`)
+
+// Charset returns the declared character set of the response, checking the
+// Content-Type header first and then any <meta> declaration near the start
+// of the document. It defaults to utf-8.
+func (r *Response) Charset() string {
+	if _, params, err := mime.ParseMediaType(r.ContentType); err == nil {
+		if cs := params["charset"]; cs != "" {
+			return strings.ToLower(cs)
+		}
+	}
+
+	head := r.Body
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	if matches := metaCharsetRegex.FindSubmatch(head); matches != nil {
+		return strings.ToLower(string(matches[1]))
+	}
+
+	return "utf-8"
+}
+
+// Text returns the body decoded to UTF-8 according to its declared charset.
+// Unknown charsets fall back to the raw bytes.
+func (r *Response) Text() string {
+	charset := r.Charset()
+	if charset == "utf-8" || charset == "utf8" {
+		return string(r.Body)
+	}
+
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return string(r.Body)
+	}
+
+	decoded, err := io.ReadAll(enc.NewDecoder().Reader(bytes.NewReader(r.Body)))
+	if err != nil {
+		return string(r.Body)
+	}
+	return string(decoded)
+}
+
+// policyTransport enforces the network policy on every request, including
+// each hop of a redirect chain
+type policyTransport struct {
+	policy *Policy
+	base   http.RoundTripper
+	trace  func(format string, args ...interface{})
+}
+
+func newPolicyTransport(opts Options) *policyTransport {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.Proxy != "" {
+		proxyURL, err := url.Parse(opts.Proxy)
+		if err != nil || proxyURL.Host == "" {
+			base.Proxy = func(*http.Request) (*url.URL, error) {
+				return nil, fmt.Errorf("invalid proxy URL %q", opts.Proxy)
+			}
+		} else {
+			base.Proxy = http.ProxyURL(proxyURL)
+		}
+	}
+
+	trace := opts.Trace
+	if trace == nil {
+		trace = func(string, ...interface{}) {}
+	}
+
+	return &policyTransport{policy: opts.Policy, base: base, trace: trace}
+}
+
+func (t *policyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.policy.Check(req.URL.Hostname()); err != nil {
+		t.trace("network: denied %s %s: %v", req.Method, req.URL.Redacted(), err)
+		return nil, err
+	}
+
+	t.trace("network: %s %s", req.Method, req.URL.Redacted())
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,159 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_Get_LimitsBodySize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("a", 100)))
+	}))
+	defer server.Close()
+
+	client := New(Options{MaxBytes: 10})
+	resp, err := client.Get(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if len(resp.Body) != 10 {
+		t.Errorf("len(Body) = %d, want 10", len(resp.Body))
+	}
+	if !resp.Truncated {
+		t.Error("Truncated = false, want true")
+	}
+}
+
+func TestClient_Get_LimitsRedirects(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, server.URL+"/loop", http.StatusFound)
+	}))
+	defer server.Close()
+
+	client := New(Options{MaxRedirects: 2})
+	_, err := client.Get(context.Background(), server.URL)
+	if !errors.Is(err, ErrTooManyRedirects) {
+		t.Errorf("Get() error = %v, want ErrTooManyRedirects", err)
+	}
+}
+
+func TestClient_Get_NoRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/elsewhere", http.StatusFound)
+	}))
+	defer server.Close()
+
+	_, err := New(Options{MaxRedirects: NoRedirects}).Get(context.Background(), server.URL)
+	if !errors.Is(err, ErrTooManyRedirects) {
+		t.Errorf("Get() error = %v, want ErrTooManyRedirects", err)
+	}
+}
+
+func TestClient_Get_FollowsRedirects(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/new", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("moved"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	resp, err := New(Options{}).Get(context.Background(), server.URL+"/old")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if resp.URL.Path != "/new" {
+		t.Errorf("URL.Path = %q, want /new", resp.URL.Path)
+	}
+	if string(resp.Body) != "moved" {
+		t.Errorf("Body = %q, want moved", resp.Body)
+	}
+}
+
+func TestClient_Get_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := New(Options{Timeout: 50 * time.Millisecond})
+
+	if _, err := client.Get(context.Background(), server.URL+"/missing"); err == nil {
+		t.Error("Get() on 404 should return an error")
+	}
+	if _, err := client.Get(context.Background(), server.URL+"/slow"); err == nil {
+		t.Error("Get() past the timeout should return an error")
+	}
+}
+
+func TestClient_Get_SendsUserAgent(t *testing.T) {
+	var gotAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAgent = r.UserAgent()
+	}))
+	defer server.Close()
+
+	if _, err := New(Options{}).Get(context.Background(), server.URL); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if gotAgent != DefaultUserAgent {
+		t.Errorf("User-Agent = %q, want %q", gotAgent, DefaultUserAgent)
+	}
+}
+
+func TestResponse_Text(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		expected    string
+	}{
+		{
+			name:        "UTF-8 default",
+			contentType: "text/html",
+			body:        []byte("Caf\xc3\xa9"),
+			expected:    "Café",
+		},
+		{
+			name:        "Latin-1 from header",
+			contentType: "text/html; charset=ISO-8859-1",
+			body:        []byte("Caf\xe9"),
+			expected:    "Café",
+		},
+		{
+			name:        "Windows-1252 from meta tag",
+			contentType: "text/html",
+			body:        []byte(`<meta charset="windows-1252"><title>Caf` + "\xe9" + `</title>`),
+			expected:    `<meta charset="windows-1252"><title>Café</title>`,
+		},
+		{
+			name:        "Unknown charset falls back to raw bytes",
+			contentType: "text/html; charset=x-made-up",
+			body:        []byte("plain"),
+			expected:    "plain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &Response{ContentType: tt.contentType, Body: tt.body}
+			if got := resp.Text(); got != tt.expected {
+				t.Errorf("Text() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
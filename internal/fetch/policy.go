@@ -0,0 +1,80 @@
+package fetch
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+// ErrDenied is returned for requests blocked by the network policy
+var ErrDenied = errors.New("request denied by network policy")
+
+// Policy decides which hosts outbound requests may reach
+type Policy struct {
+	Mode  string
+	Allow []string
+	Deny  []string
+}
+
+// NewPolicy builds a policy from the network configuration. An empty mode
+// means "off": nothing leaves the machine until the network is turned on.
+func NewPolicy(cfg types.NetworkConfig) *Policy {
+	mode := strings.ToLower(strings.TrimSpace(cfg.Mode))
+	if mode == "" {
+		mode = types.NetworkModeOff
+	}
+	return &Policy{Mode: mode, Allow: cfg.Allow, Deny: cfg.Deny}
+}
+
+// Check returns nil if a request to host is permitted, or an error wrapping
+// ErrDenied explaining why not. A nil policy permits everything.
+func (p *Policy) Check(host string) error {
+	if p == nil {
+		return nil
+	}
+
+	host = strings.ToLower(host)
+	switch p.Mode {
+	case types.NetworkModeOff:
+		return fmt.Errorf("%w: network is off", ErrDenied)
+	case types.NetworkModeAllowlist:
+		if matchesAny(host, p.Deny) {
+			return fmt.Errorf("%w: %s is on the deny list", ErrDenied, host)
+		}
+		if !matchesAny(host, p.Allow) {
+			return fmt.Errorf("%w: %s is not on the allow list", ErrDenied, host)
+		}
+		return nil
+	case types.NetworkModeOn:
+		if matchesAny(host, p.Deny) {
+			return fmt.Errorf("%w: %s is on the deny list", ErrDenied, host)
+		}
+		return nil
+	default:
+		// Fail closed on a mode we don't understand
+		return fmt.Errorf("%w: unknown network mode %q", ErrDenied, p.Mode)
+	}
+}
+
+// matchesAny reports whether host matches one of the patterns. A pattern
+// matches the host exactly; "*.example.com" matches any subdomain.
+func matchesAny(host string, patterns []string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == "" {
+			continue
+		}
+		if strings.HasPrefix(pattern, "*.") {
+			if strings.HasSuffix(host, pattern[1:]) {
+				return true
+			}
+			continue
+		}
+		if host == pattern {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+func TestPolicy_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		network types.NetworkConfig
+		host    string
+		allowed bool
+	}{
+		{"Default mode is off", types.NetworkConfig{}, "www.youtube.com", false},
+		{"On allows any host", types.NetworkConfig{Mode: "on"}, "example.com", true},
+		{"On honours deny list", types.NetworkConfig{Mode: "on", Deny: []string{"www.youtube.com"}}, "www.youtube.com", false},
+		{"Off denies everything", types.NetworkConfig{Mode: "off"}, "example.com", false},
+		{"Mode is case-insensitive", types.NetworkConfig{Mode: "OFF"}, "example.com", false},
+		{"Allowlist permits listed host", types.NetworkConfig{Mode: "allowlist", Allow: []string{"api.github.com"}}, "api.github.com", true},
+		{"Allowlist denies unlisted host", types.NetworkConfig{Mode: "allowlist", Allow: []string{"api.github.com"}}, "www.youtube.com", false},
+		{"Wildcard matches subdomain", types.NetworkConfig{Mode: "allowlist", Allow: []string{"*.example.com"}}, "docs.example.com", true},
+		{"Wildcard does not match apex", types.NetworkConfig{Mode: "allowlist", Allow: []string{"*.example.com"}}, "example.com", false},
+		{"Deny beats allow", types.NetworkConfig{Mode: "allowlist", Allow: []string{"*.example.com"}, Deny: []string{"secret.example.com"}}, "secret.example.com", false},
+		{"Host matching is case-insensitive", types.NetworkConfig{Mode: "allowlist", Allow: []string{"Example.com"}}, "EXAMPLE.COM", true},
+		{"Unknown mode fails closed", types.NetworkConfig{Mode: "sometimes"}, "example.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewPolicy(tt.network).Check(tt.host)
+			if (err == nil) != tt.allowed {
+				t.Errorf("Check(%q) = %v, want allowed=%v", tt.host, err, tt.allowed)
+			}
+			if err != nil && !errors.Is(err, ErrDenied) {
+				t.Errorf("Check(%q) error %v does not wrap ErrDenied", tt.host, err)
+			}
+		})
+	}
+}
+
+func TestClient_DeniedRequestsAreTraced(t *testing.T) {
+	hit := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+	}))
+	defer server.Close()
+
+	var trace []string
+	client := New(Options{
+		Policy: NewPolicy(types.NetworkConfig{Mode: types.NetworkModeOff}),
+		Trace: func(format string, args ...interface{}) {
+			trace = append(trace, fmt.Sprintf(format, args...))
+		},
+	})
+
+	_, err := client.Get(context.Background(), server.URL)
+	if !errors.Is(err, ErrDenied) {
+		t.Fatalf("Get() error = %v, want ErrDenied", err)
+	}
+	if hit {
+		t.Error("denied request reached the server")
+	}
+	if len(trace) != 1 || !strings.Contains(trace[0], "denied") {
+		t.Errorf("trace = %v, want a single denial line", trace)
+	}
+}
+
+func TestClient_PolicyAppliesToRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://tracker.invalid/", http.StatusFound)
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := New(Options{
+		Policy: NewPolicy(types.NetworkConfig{Mode: "allowlist", Allow: []string{serverURL.Hostname()}}),
+	})
+
+	if _, err := client.Get(context.Background(), server.URL); !errors.Is(err, ErrDenied) {
+		t.Errorf("Get() error = %v, want ErrDenied for the redirect target", err)
+	}
+}
+
+func TestFromConfig_ProxyAndUserAgent(t *testing.T) {
+	var gotURL, gotAgent string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotURL = r.URL.String()
+		gotAgent = r.UserAgent()
+		_, _ = w.Write([]byte("via proxy"))
+	}))
+	defer proxy.Close()
+
+	cfg := &types.Config{
+		Network: types.NetworkConfig{
+			Mode:      types.NetworkModeOn,
+			Proxy:     proxy.URL,
+			UserAgent: "custom-agent/1.0",
+		},
+	}
+
+	resp, err := FromConfig(cfg, Options{}).Get(context.Background(), "http://upstream.invalid/page")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if string(resp.Body) != "via proxy" {
+		t.Errorf("Body = %q, want the proxy's response", resp.Body)
+	}
+	if gotURL != "http://upstream.invalid/page" {
+		t.Errorf("proxy saw URL %q, want the upstream URL", gotURL)
+	}
+	if gotAgent != "custom-agent/1.0" {
+		t.Errorf("User-Agent = %q, want custom-agent/1.0", gotAgent)
+	}
+}
+
+func TestFromConfig_InvalidProxy(t *testing.T) {
+	cfg := &types.Config{Network: types.NetworkConfig{Proxy: "::not a url"}}
+	if _, err := FromConfig(cfg, Options{}).Get(context.Background(), "http://upstream.invalid/"); err == nil {
+		t.Error("Get() with an invalid proxy should fail rather than bypass it")
+	}
+}
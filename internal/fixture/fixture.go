@@ -0,0 +1,401 @@
+package fixture
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	headingRegex = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*$`)
+	fenceRegex   = regexp.MustCompile("^\\s*(```+|~~~+)")
+)
+
+// File is a markdown document containing example transformations
+type File struct {
+	Path string
+	// Config is the config file the fixtures need, from front matter,
+	// resolved relative to the fixture file; empty if none is given
+	Config string
+	Cases  []Case
+
+	content string
+}
+
+// Case is one input and its expected output
+type Case struct {
+	Name     string
+	Line     int
+	Input    string
+	Expected string
+
+	// expected marks where Expected sits in the file so it can be rewritten
+	expected span
+}
+
+type span struct {
+	start, end int
+	// table values must stay on one line
+	table bool
+}
+
+type frontMatter struct {
+	Config string `yaml:"config"`
+}
+
+// Load reads and parses the fixture file at path
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(path, string(data))
+}
+
+// Parse extracts the cases from a fixture document. Two layouts are
+// recognised:
+//
+//   - an "Input" label followed by a fenced block, <input> tag block or
+//     paragraph, paired with an "Expected Output" label and its block, in
+//     either order; labels may be headings or bold text
+//   - tables with "Input" and "Output" (or "Expected Output") columns, whose
+//     cells may be wrapped in backticks
+//
+// Optional YAML front matter may name a config file with "config:".
+func Parse(path, content string) (*File, error) {
+	file := &File{Path: path, content: content}
+
+	lines := splitLines(content)
+	i := 0
+	if len(lines) > 0 && strings.TrimSpace(lines[0].text) == "---" {
+		end := -1
+		for j := 1; j < len(lines); j++ {
+			if strings.TrimSpace(lines[j].text) == "---" {
+				end = j
+				break
+			}
+		}
+		if end < 0 {
+			return nil, fmt.Errorf("%s: unterminated front matter", path)
+		}
+
+		var meta frontMatter
+		raw := content[lines[1].start:lines[end].start]
+		if err := yaml.Unmarshal([]byte(raw), &meta); err != nil {
+			return nil, fmt.Errorf("%s: invalid front matter: %w", path, err)
+		}
+		if meta.Config != "" {
+			file.Config = meta.Config
+			if !filepath.IsAbs(file.Config) {
+				file.Config = filepath.Join(filepath.Dir(path), file.Config)
+			}
+		}
+		i = end + 1
+	}
+
+	p := &parser{file: file, lines: lines}
+	p.run(i)
+	return file, nil
+}
+
+type line struct {
+	text       string
+	start, end int // byte offsets of the text, excluding the newline
+}
+
+func splitLines(content string) []line {
+	lines := make([]line, 0)
+	start := 0
+	for start <= len(content) {
+		end := strings.IndexByte(content[start:], '\n')
+		if end < 0 {
+			if start < len(content) {
+				lines = append(lines, line{text: content[start:], start: start, end: len(content)})
+			}
+			break
+		}
+		text := strings.TrimSuffix(content[start:start+end], "\r")
+		lines = append(lines, line{text: text, start: start, end: start + len(text)})
+		start += end + 1
+	}
+	return lines
+}
+
+type parser struct {
+	file  *File
+	lines []line
+
+	name string
+	// pending holds the label waiting for its block, and the halves of a
+	// case collected so far
+	pending   string
+	input     *block
+	expected  *block
+	inputLine int
+}
+
+type block struct {
+	value string
+	span  span
+}
+
+const (
+	labelInput    = "input"
+	labelExpected = "expected"
+)
+
+func (p *parser) run(i int) {
+	for i < len(p.lines) {
+		text := p.lines[i].text
+		trimmed := strings.TrimSpace(text)
+
+		if label := labelOf(trimmed); label != "" {
+			p.pending = label
+			i++
+			continue
+		}
+
+		if matches := headingRegex.FindStringSubmatch(trimmed); matches != nil {
+			p.name = matches[1]
+			p.pending = ""
+			i++
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "|") {
+			i = p.table(i)
+			continue
+		}
+
+		if p.pending == "" || trimmed == "" {
+			i++
+			continue
+		}
+
+		blockLine := i
+		var b *block
+		b, i = p.block(i)
+		if p.pending == labelInput {
+			p.input = b
+			p.inputLine = blockLine + 1
+		} else {
+			p.expected = b
+		}
+		p.pending = ""
+
+		if p.input != nil && p.expected != nil {
+			p.file.Cases = append(p.file.Cases, Case{
+				Name:     p.name,
+				Line:     p.inputLine,
+				Input:    p.input.value,
+				Expected: p.expected.value,
+				expected: p.expected.span,
+			})
+			p.input, p.expected = nil, nil
+		}
+	}
+}
+
+// labelOf returns labelInput or labelExpected if text is such a label,
+// e.g. "**Input:**", "## Expected Output" or "Expected:"
+func labelOf(text string) string {
+	text = strings.TrimLeft(text, "#")
+	text = strings.TrimSpace(strings.ReplaceAll(text, "*", ""))
+	text = strings.ToLower(strings.TrimSuffix(text, ":"))
+	switch text {
+	case "input":
+		return labelInput
+	case "expected output", "expected", "output":
+		return labelExpected
+	}
+	return ""
+}
+
+// block reads the fenced block, <input> tag block or paragraph starting at
+// line i and returns it with the index of the following line
+func (p *parser) block(i int) (*block, int) {
+	first := p.lines[i]
+	trimmed := strings.TrimSpace(first.text)
+
+	if matches := fenceRegex.FindStringSubmatch(first.text); matches != nil {
+		fence := matches[1]
+		j := i + 1
+		for j < len(p.lines) && !strings.HasPrefix(strings.TrimSpace(p.lines[j].text), fence) {
+			j++
+		}
+		return p.spanOf(i+1, j), j + 1
+	}
+
+	if tag, ok := openingTag(trimmed); ok {
+		closing := "</" + tag + ">"
+		j := i + 1
+		for j < len(p.lines) && strings.TrimSpace(p.lines[j].text) != closing {
+			j++
+		}
+		return p.spanOf(i+1, j), j + 1
+	}
+
+	j := i
+	for j < len(p.lines) {
+		text := strings.TrimSpace(p.lines[j].text)
+		if text == "" || headingRegex.MatchString(text) || labelOf(text) != "" {
+			break
+		}
+		j++
+	}
+	return p.spanOf(i, j), j
+}
+
+// openingTag recognises a line holding only <name>
+func openingTag(text string) (string, bool) {
+	if !strings.HasPrefix(text, "<") || !strings.HasSuffix(text, ">") || strings.HasPrefix(text, "</") {
+		return "", false
+	}
+	tag := text[1 : len(text)-1]
+	if tag == "" || strings.ContainsAny(tag, " /") {
+		return "", false
+	}
+	return tag, true
+}
+
+// spanOf returns the text of lines [from, to) as a block
+func (p *parser) spanOf(from, to int) *block {
+	if to > len(p.lines) {
+		to = len(p.lines)
+	}
+	if from >= to {
+		// Empty block: point at the start of line "to" so an update inserts there
+		offset := len(p.file.content)
+		if to < len(p.lines) {
+			offset = p.lines[to].start
+		}
+		return &block{span: span{start: offset, end: offset}}
+	}
+
+	start, end := p.lines[from].start, p.lines[to-1].end
+	return &block{value: p.file.content[start:end], span: span{start: start, end: end}}
+}
+
+// table reads a markdown table starting at line i, adding a case for every
+// row if it has input and output columns, and returns the following line
+func (p *parser) table(i int) int {
+	header := splitRow(p.lines[i])
+	end := i
+	for end < len(p.lines) && strings.HasPrefix(strings.TrimSpace(p.lines[end].text), "|") {
+		end++
+	}
+
+	inputCol, outputCol := -1, -1
+	for col, cell := range header {
+		switch labelOf(cell.text) {
+		case labelInput:
+			inputCol = col
+		case labelExpected:
+			outputCol = col
+		}
+	}
+	if inputCol < 0 || outputCol < 0 {
+		return end
+	}
+
+	// Skip the header and the |---|---| delimiter row
+	for row := i + 2; row < end; row++ {
+		cells := splitRow(p.lines[row])
+		if len(cells) <= inputCol || len(cells) <= outputCol {
+			continue
+		}
+
+		input := cells[inputCol]
+		output := cells[outputCol]
+		p.file.Cases = append(p.file.Cases, Case{
+			Name:     fmt.Sprintf("%s: %s", p.name, unquote(input.text)),
+			Line:     row + 1,
+			Input:    unquote(input.text),
+			Expected: unquote(output.text),
+			expected: output.valueSpan(),
+		})
+	}
+
+	return end
+}
+
+type cell struct {
+	text  string
+	start int // byte offset of text in the document
+}
+
+// valueSpan covers the cell value inside any backticks
+func (c cell) valueSpan() span {
+	start, end := c.start, c.start+len(c.text)
+	if len(c.text) >= 2 && strings.HasPrefix(c.text, "`") && strings.HasSuffix(c.text, "`") {
+		start++
+		end--
+	}
+	return span{start: start, end: end, table: true}
+}
+
+// splitRow splits a table row into trimmed cells with their offsets
+func splitRow(l line) []cell {
+	text := l.text
+	cells := make([]cell, 0)
+
+	// Offsets of the separating pipes
+	pipes := make([]int, 0)
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\\' {
+			i++
+			continue
+		}
+		if text[i] == '|' {
+			pipes = append(pipes, i)
+		}
+	}
+
+	for k := 0; k+1 < len(pipes); k++ {
+		raw := text[pipes[k]+1 : pipes[k+1]]
+		trimmedLeft := strings.TrimLeft(raw, " \t")
+		value := strings.TrimRight(trimmedLeft, " \t")
+		offset := l.start + pipes[k] + 1 + len(raw) - len(trimmedLeft)
+		cells = append(cells, cell{text: value, start: offset})
+	}
+	return cells
+}
+
+// unquote removes backticks wrapping a table cell value
+func unquote(s string) string {
+	if len(s) >= 2 && strings.HasPrefix(s, "`") && strings.HasSuffix(s, "`") {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// Update returns the file content with the expected output of each case
+// replaced by the matching entry of actual, keyed by case index
+func (f *File) Update(actual map[int]string) (string, error) {
+	indexes := make([]int, 0, len(actual))
+	for index := range actual {
+		indexes = append(indexes, index)
+	}
+	// Replace from the end so earlier offsets stay valid
+	sort.Sort(sort.Reverse(sort.IntSlice(indexes)))
+
+	content := f.content
+	for _, index := range indexes {
+		c := f.Cases[index]
+		value := actual[index]
+		if c.expected.table && strings.ContainsAny(value, "\n`|") {
+			return "", fmt.Errorf("%s:%d: output %q cannot be written to a table cell", f.Path, c.Line, value)
+		}
+		if c.expected.start == c.expected.end && !c.expected.table {
+			// An empty block has no line of its own yet
+			value += "\n"
+		}
+		content = content[:c.expected.start] + value + content[c.expected.end:]
+	}
+	return content, nil
+}
@@ -0,0 +1,198 @@
+package fixture
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+const fencedFixture = `---
+config: ../config.yaml
+---
+# JIRA Examples
+
+### Example 1: Basic
+
+**Input:**
+` + "```" + `
+PLAT-192
+
+blinc - webhook proxy logs
+` + "```" + `
+
+**Expected Output:**
+` + "```" + `
+[PLAT-192: blinc - webhook proxy logs](https://example.atlassian.net/browse/PLAT-192)
+` + "```" + `
+
+### Example 2: Empty expectation
+
+**Input:**
+` + "```" + `
+PLAT-1
+` + "```" + `
+
+**Expected Output:**
+` + "```" + `
+` + "```" + `
+`
+
+const tagFixture = `## Expected Output
+[org/repo#1: Title](https://github.com/org/repo/issues/1)
+
+## Input
+<input>
+org
+repo
+Title #1
+</input>
+`
+
+const tableFixture = `## Seven Digits
+
+| Input      | Notes    | Output                       |
+|------------|----------|------------------------------|
+| ` + "`1234567`" + `  | Match    | ` + "`📞 [123-4567](tel:1234567)`" + ` |
+| ` + "`123 4567`" + ` | No Match | ` + "`123 4567`" + `                   |
+
+| Name | Value |
+|------|-------|
+| a    | b     |
+`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		wantConfig string
+		want       []Case
+	}{
+		{
+			name:       "Fenced blocks with front matter",
+			content:    fencedFixture,
+			wantConfig: filepath.Join("examples", "config.yaml"),
+			want: []Case{
+				{
+					Name:     "Example 1: Basic",
+					Line:     9,
+					Input:    "PLAT-192\n\nblinc - webhook proxy logs",
+					Expected: "[PLAT-192: blinc - webhook proxy logs](https://example.atlassian.net/browse/PLAT-192)",
+				},
+				{Name: "Example 2: Empty expectation", Line: 23, Input: "PLAT-1", Expected: ""},
+			},
+		},
+		{
+			name:    "Input tag with expectation first",
+			content: tagFixture,
+			want: []Case{
+				{Line: 5, Input: "org\nrepo\nTitle #1", Expected: "[org/repo#1: Title](https://github.com/org/repo/issues/1)"},
+			},
+		},
+		{
+			name:    "Table rows",
+			content: tableFixture,
+			want: []Case{
+				{Name: "Seven Digits: 1234567", Line: 5, Input: "1234567", Expected: "📞 [123-4567](tel:1234567)"},
+				{Name: "Seven Digits: 123 4567", Line: 6, Input: "123 4567", Expected: "123 4567"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := Parse(filepath.Join("examples", "jira", "fixture.md"), tt.content)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if file.Config != tt.wantConfig {
+				t.Errorf("Config = %q, want %q", file.Config, tt.wantConfig)
+			}
+			if len(file.Cases) != len(tt.want) {
+				t.Fatalf("Parse() returned %d cases, want %d: %+v", len(file.Cases), len(tt.want), file.Cases)
+			}
+			for i, want := range tt.want {
+				got := file.Cases[i]
+				if got.Name != want.Name || got.Line != want.Line || got.Input != want.Input || got.Expected != want.Expected {
+					t.Errorf("Cases[%d] = %q line %d %q → %q, want %q line %d %q → %q",
+						i, got.Name, got.Line, got.Input, got.Expected, want.Name, want.Line, want.Input, want.Expected)
+				}
+			}
+		})
+	}
+}
+
+func TestFile_Update(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		actual  map[int]string
+		check   func(t *testing.T, updated *File)
+	}{
+		{
+			name:    "Fenced and empty blocks",
+			content: fencedFixture,
+			actual:  map[int]string{0: "first", 1: "second\nline"},
+			check: func(t *testing.T, updated *File) {
+				if updated.Cases[0].Expected != "first" || updated.Cases[1].Expected != "second\nline" {
+					t.Errorf("updated expectations = %q, %q", updated.Cases[0].Expected, updated.Cases[1].Expected)
+				}
+			},
+		},
+		{
+			name:    "Paragraph",
+			content: tagFixture,
+			actual:  map[int]string{0: "new output"},
+			check: func(t *testing.T, updated *File) {
+				if updated.Cases[0].Expected != "new output" || updated.Cases[0].Input != "org\nrepo\nTitle #1" {
+					t.Errorf("updated case = %+v", updated.Cases[0])
+				}
+			},
+		},
+		{
+			name:    "Table cell keeps backticks",
+			content: tableFixture,
+			actual:  map[int]string{1: "changed"},
+			check: func(t *testing.T, updated *File) {
+				if updated.Cases[0].Expected != "📞 [123-4567](tel:1234567)" || updated.Cases[1].Expected != "changed" {
+					t.Errorf("updated expectations = %q, %q", updated.Cases[0].Expected, updated.Cases[1].Expected)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := Parse("fixture.md", tt.content)
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, err := file.Update(tt.actual)
+			if err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+			updated, err := Parse("fixture.md", content)
+			if err != nil {
+				t.Fatalf("Parse() of updated content error = %v", err)
+			}
+			if len(updated.Cases) != len(file.Cases) {
+				t.Fatalf("updated file has %d cases, want %d:\n%s", len(updated.Cases), len(file.Cases), content)
+			}
+			tt.check(t, updated)
+		})
+	}
+
+	file, err := Parse("fixture.md", tableFixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.Update(map[int]string{0: "two\nlines"}); err == nil {
+		t.Error("Update() should refuse multi-line output in a table cell")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	got := Diff("a\nb\nc", "a\nx\nc")
+	want := "  a\n- b\n+ x\n  c\n"
+	if got != want {
+		t.Errorf("Diff() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,360 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/erebusbat/markdown-tool/internal/gitutil"
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+const (
+	// DefaultHost is the public GitHub host
+	DefaultHost = "github.com"
+	// DefaultAPIURL is the REST API of github.com
+	DefaultAPIURL = "https://api.github.com"
+)
+
+// Host is a GitHub-compatible server with its own defaults and mappings
+type Host struct {
+	// Name is the lowercased hostname used in URLs
+	Name        string
+	BaseURL     string
+	DefaultOrg  string
+	DefaultRepo string
+	Mappings    map[string]string
+	// APIURL and Token are used for REST API lookups
+	APIURL string
+	Token  string
+
+	// compiled holds Mappings in order of precedence, set when the hosts
+	// are built
+	compiled []mapping
+}
+
+// Hosts returns github.com, configured by the top-level github settings,
+// followed by each entry of github.hosts. An entry for github.com itself
+// overrides the top-level settings it sets.
+func Hosts(cfg *types.Config) []Host {
+	return buildHosts(cfg)
+}
+
+// HostSet is the hosts of one configuration with their mappings compiled.
+// Parsers, enrichers and writers build one when they are constructed, so
+// lookups made for each token don't rebuild the hosts or recompile mapping
+// patterns.
+type HostSet struct {
+	hosts []Host
+}
+
+// NewHostSet builds the hosts of cfg
+func NewHostSet(cfg *types.Config) *HostSet {
+	return &HostSet{hosts: buildHosts(cfg)}
+}
+
+func buildHosts(cfg *types.Config) []Host {
+	public := Host{
+		Name:        DefaultHost,
+		BaseURL:     "https://" + DefaultHost,
+		DefaultOrg:  cfg.GitHub.DefaultOrg,
+		DefaultRepo: cfg.GitHub.DefaultRepo,
+		Mappings:    cfg.GitHub.Mappings,
+		APIURL:      DefaultAPIURL,
+		Token:       cfg.GitHub.API.Token,
+	}
+	if cfg.GitHub.API.BaseURL != "" {
+		public.APIURL = strings.TrimRight(cfg.GitHub.API.BaseURL, "/")
+	}
+
+	hosts := []Host{public}
+	for _, hc := range cfg.GitHub.Hosts {
+		name := normalizeHost(hc.Host)
+		if name == "" {
+			continue
+		}
+
+		host := Host{
+			Name:        name,
+			BaseURL:     strings.TrimRight(hc.BaseURL, "/"),
+			DefaultOrg:  hc.DefaultOrg,
+			DefaultRepo: hc.DefaultRepo,
+			Mappings:    hc.Mappings,
+			APIURL:      strings.TrimRight(hc.APIURL, "/"),
+			Token:       hc.Token,
+		}
+		if name == DefaultHost {
+			hosts[0] = overlay(hosts[0], host)
+			continue
+		}
+		if host.BaseURL == "" {
+			host.BaseURL = "https://" + name
+		}
+		if host.APIURL == "" {
+			host.APIURL = host.BaseURL + "/api/v3"
+		}
+		hosts = append(hosts, host)
+	}
+	for i := range hosts {
+		hosts[i].compiled = compileMappings(hosts[i].Mappings)
+	}
+	return hosts
+}
+
+// overlay returns base with the fields set in over
+func overlay(base, over Host) Host {
+	if over.BaseURL != "" {
+		base.BaseURL = over.BaseURL
+	}
+	if over.DefaultOrg != "" {
+		base.DefaultOrg = over.DefaultOrg
+	}
+	if over.DefaultRepo != "" {
+		base.DefaultRepo = over.DefaultRepo
+	}
+	if over.Mappings != nil {
+		base.Mappings = over.Mappings
+	}
+	if over.APIURL != "" {
+		base.APIURL = over.APIURL
+	}
+	if over.Token != "" {
+		base.Token = over.Token
+	}
+	return base
+}
+
+// Lookup returns the host of cfg serving hostname; see HostSet.Lookup
+func Lookup(cfg *types.Config, hostname string) (Host, bool) {
+	return NewHostSet(cfg).Lookup(hostname)
+}
+
+// Lookup returns the host serving hostname. www.github.com is treated as
+// github.com, and a host also matches the hostname of its base URL.
+func (s *HostSet) Lookup(hostname string) (Host, bool) {
+	hostname = normalizeHost(hostname)
+	if hostname == "www."+DefaultHost {
+		hostname = DefaultHost
+	}
+
+	for _, host := range s.hosts {
+		if host.Name == hostname {
+			return host, true
+		}
+		if u, err := url.Parse(host.BaseURL); err == nil && normalizeHost(u.Host) == hostname {
+			return host, true
+		}
+	}
+	return Host{}, false
+}
+
+// ForRepo returns the host of cfg for org/repo; see HostSet.ForRepo
+func ForRepo(cfg *types.Config, org, repo string) Host {
+	return NewHostSet(cfg).ForRepo(org, repo)
+}
+
+// ForRepo picks the host a repository most likely lives on when the input
+// doesn't say, such as text copied from the GitHub UI: the first host with
+// a mapping for org/repo, then the first whose default org is org, and
+// otherwise github.com.
+func (s *HostSet) ForRepo(org, repo string) Host {
+	for _, host := range s.hosts {
+		if _, ok := host.mapped(org, repo); ok {
+			return host
+		}
+	}
+	for _, host := range s.hosts {
+		if host.DefaultOrg != "" && strings.EqualFold(host.DefaultOrg, org) {
+			return host
+		}
+	}
+	return s.hosts[0]
+}
+
+// Default returns the default host of cfg; see HostSet.Default
+func Default(cfg *types.Config) Host {
+	return NewHostSet(cfg).Default()
+}
+
+// Default returns the host whose default org and repo are used for input
+// that names neither: github.com if it has both, otherwise the first
+// configured host that does.
+func (s *HostSet) Default() Host {
+	for _, host := range s.hosts {
+		if host.DefaultOrg != "" && host.DefaultRepo != "" {
+			return host
+		}
+	}
+	return s.hosts[0]
+}
+
+// Current returns the current repository for cfg; see HostSet.Current
+func Current(cfg *types.Config, dir string) (host Host, org, repo string, ok bool) {
+	return NewHostSet(cfg).Current(dir)
+}
+
+// Current returns the repository that input naming none refers to: the
+// default org and repo from Default, or else the remote on a GitHub host of
+// the git repository containing dir, preferring "origin"
+func (s *HostSet) Current(dir string) (host Host, org, repo string, ok bool) {
+	if host := s.Default(); host.DefaultOrg != "" && host.DefaultRepo != "" {
+		return host, host.DefaultOrg, host.DefaultRepo, true
+	}
+	if dir == "" {
+		return Host{}, "", "", false
+	}
+
+	for _, remote := range gitutil.FindRemotes(dir) {
+		remoteHost, known := s.Lookup(remote.Host)
+		if !known || strings.Contains(remote.Org, "/") {
+			continue
+		}
+		if !ok || remote.Name == "origin" {
+			host, org, repo, ok = remoteHost, remote.Org, remote.Repo, true
+		}
+	}
+	return host, org, repo, ok
+}
+
+// DisplayName returns the link text for org/repo, applying the host's
+// mappings. Keys are compared case-insensitively since viper lowercases them.
+func (h Host) DisplayName(org, repo string) string {
+	if display, ok := h.mapped(org, repo); ok {
+		return display
+	}
+	return fmt.Sprintf("%s/%s", org, repo)
+}
+
+// mapped returns the display name of the first mapping, in order of
+// precedence, that matches org/repo
+func (h Host) mapped(org, repo string) (string, bool) {
+	orgRepo := fmt.Sprintf("%s/%s", org, repo)
+	for _, m := range h.mappings() {
+		if display, ok := m.apply(orgRepo); ok {
+			return display, true
+		}
+	}
+	return "", false
+}
+
+// mappings returns the host's compiled mappings, compiling them for a Host
+// not built from a config
+func (h Host) mappings() []mapping {
+	if h.compiled == nil && len(h.Mappings) > 0 {
+		return compileMappings(h.Mappings)
+	}
+	return h.compiled
+}
+
+// URL joins path segments onto the host's base URL
+func (h Host) URL(segments ...string) string {
+	return h.BaseURL + "/" + strings.Join(segments, "/")
+}
+
+func normalizeHost(host string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(host), "."))
+}
+
+// Resolve finds the repository name refers to in cfg; see HostSet.Resolve
+func Resolve(cfg *types.Config, name string) (host Host, org, repo string, ok bool) {
+	return NewHostSet(cfg).Resolve(name)
+}
+
+// Resolve finds the repository a shorthand name refers to, inverting the
+// mappings so a display name leads back to the real repository. name is
+// either org/repo or a bare repository name:
+//
+//   - a mapping whose display name is name wins, on any host
+//   - a bare name then matches the repository part of a display name, e.g.
+//     "API" for "CompanyCam/API"
+//   - a bare name then matches the repository of a literal mapping key, or
+//     a host's default repository
+//   - otherwise org/repo is taken as written; any other bare name, such as
+//     the "C" of "C#7" in prose, is not a repository
+func (s *HostSet) Resolve(name string) (host Host, org, repo string, ok bool) {
+	hosts := s.hosts
+	for _, host := range hosts {
+		if org, repo, ok := host.inverse(name); ok {
+			return host, org, repo, true
+		}
+	}
+
+	if strings.Contains(name, "/") {
+		org, repo, ok = strings.Cut(name, "/")
+		if !ok || org == "" || repo == "" || strings.Contains(repo, "/") {
+			return Host{}, "", "", false
+		}
+		return s.ForRepo(org, repo), org, repo, true
+	}
+
+	for _, host := range hosts {
+		if org, repo, ok := host.inverseRepo(name); ok {
+			return host, org, repo, true
+		}
+	}
+	for _, host := range hosts {
+		if org, repo, ok := host.mappedRepo(name); ok {
+			return host, org, repo, true
+		}
+	}
+	for _, host := range hosts {
+		if host.DefaultOrg != "" && strings.EqualFold(host.DefaultRepo, name) {
+			return host, host.DefaultOrg, host.DefaultRepo, true
+		}
+	}
+	return Host{}, "", "", false
+}
+
+// mappedRepo returns the org/repo of a literal mapping key whose repository
+// is name, so "api" finds the key "acme/api" whatever it displays as
+func (h Host) mappedRepo(name string) (org, repo string, ok bool) {
+	for _, m := range h.mappings() {
+		if m.pattern != nil {
+			continue
+		}
+		if org, repo, found := strings.Cut(m.key, "/"); found && strings.EqualFold(repo, name) {
+			return h.restoreCase(org, repo)
+		}
+	}
+	return "", "", false
+}
+
+// inverse returns the org/repo whose display name is name, trying the
+// mappings in order of precedence. A candidate that a mapping of higher
+// precedence would display differently is passed over.
+func (h Host) inverse(name string) (org, repo string, ok bool) {
+	for _, m := range h.mappings() {
+		if org, repo, ok := m.reverse(name); ok && strings.EqualFold(h.DisplayName(org, repo), name) {
+			return h.restoreCase(org, repo)
+		}
+	}
+	return "", "", false
+}
+
+// inverseRepo is inverse for a bare repository name, matching the repository
+// part of display names whose org part is fixed
+func (h Host) inverseRepo(name string) (org, repo string, ok bool) {
+	for _, m := range h.mappings() {
+		displayOrg, fixed := m.displayOrg()
+		if !fixed {
+			continue
+		}
+		display := displayOrg + "/" + name
+		if org, repo, ok := m.reverse(display); ok && strings.EqualFold(h.DisplayName(org, repo), display) {
+			return h.restoreCase(org, repo)
+		}
+	}
+	return "", "", false
+}
+
+// restoreCase takes the capitalisation of the default org and repo where
+// they match, since mapping keys come back lowercased from the config
+func (h Host) restoreCase(org, repo string) (string, string, bool) {
+	if strings.EqualFold(org, h.DefaultOrg) {
+		org = h.DefaultOrg
+	}
+	if strings.EqualFold(repo, h.DefaultRepo) {
+		repo = h.DefaultRepo
+	}
+	return org, repo, true
+}
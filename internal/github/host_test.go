@@ -0,0 +1,154 @@
+package github
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+func testConfig() *types.Config {
+	return &types.Config{
+		GitHub: types.GitHubConfig{
+			DefaultOrg: "Acme",
+			Mappings:   map[string]string{"acme/api": "API"},
+			Hosts: []types.GitHubHostConfig{
+				{
+					Host:        "GitHub.Corp.example",
+					DefaultOrg:  "Platform",
+					DefaultRepo: "monolith",
+					Mappings:    map[string]string{"platform/monolith": "Mono"},
+				},
+				{Host: "git.example.com", BaseURL: "https://code.example.com/"},
+			},
+		},
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name     string
+		hostname string
+		expected string
+		baseURL  string
+		ok       bool
+	}{
+		{"Public host", "github.com", "github.com", "https://github.com", true},
+		{"www alias", "www.github.com", "github.com", "https://github.com", true},
+		{"Enterprise host ignores case", "github.corp.example", "github.corp.example", "https://github.corp.example", true},
+		{"Custom base URL", "git.example.com", "git.example.com", "https://code.example.com", true},
+		{"Base URL hostname", "code.example.com", "git.example.com", "https://code.example.com", true},
+		{"Unknown host", "gitlab.com", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, ok := Lookup(testConfig(), tt.hostname)
+			if ok != tt.ok {
+				t.Fatalf("Lookup(%q) ok = %v, want %v", tt.hostname, ok, tt.ok)
+			}
+			if host.Name != tt.expected || host.BaseURL != tt.baseURL {
+				t.Errorf("Lookup(%q) = %s %s, want %s %s", tt.hostname, host.Name, host.BaseURL, tt.expected, tt.baseURL)
+			}
+		})
+	}
+}
+
+func TestForRepo(t *testing.T) {
+	tests := []struct {
+		name     string
+		org      string
+		repo     string
+		expected string
+	}{
+		{"Mapped repo", "platform", "monolith", "github.corp.example"},
+		{"Default org", "Platform", "other", "github.corp.example"},
+		{"Public mapping wins", "acme", "api", "github.com"},
+		{"Unknown falls back to github.com", "someone", "else", "github.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ForRepo(testConfig(), tt.org, tt.repo).Name; got != tt.expected {
+				t.Errorf("ForRepo(%q, %q) = %q, want %q", tt.org, tt.repo, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHosts_OverridePublic(t *testing.T) {
+	cfg := testConfig()
+	cfg.GitHub.Hosts = append(cfg.GitHub.Hosts, types.GitHubHostConfig{Host: "github.com", DefaultRepo: "web"})
+
+	hosts := Hosts(cfg)
+	if len(hosts) != 3 {
+		t.Fatalf("Hosts() returned %d hosts, want 3", len(hosts))
+	}
+	public := hosts[0]
+	if public.DefaultOrg != "Acme" || public.DefaultRepo != "web" || public.DisplayName("acme", "api") != "API" {
+		t.Errorf("github.com = %+v, want top-level settings with default_repo web", public)
+	}
+	if got := Default(cfg).Name; got != "github.com" {
+		t.Errorf("Default() = %q, want github.com", got)
+	}
+
+	cfg.GitHub.Hosts = cfg.GitHub.Hosts[:2]
+	if got := Default(cfg).Name; got != "github.corp.example" {
+		t.Errorf("Default() without public defaults = %q, want github.corp.example", got)
+	}
+}
+
+func TestHosts_FollowsConfigEdits(t *testing.T) {
+	cfg := testConfig()
+	if host, _ := Lookup(cfg, "github.com"); host.DisplayName("acme", "web") != "acme/web" {
+		t.Fatalf("DisplayName() = %q before the mapping exists", host.DisplayName("acme", "web"))
+	}
+
+	cfg.GitHub.Mappings["acme/web"] = "Web"
+	if host, _ := Lookup(cfg, "github.com"); host.DisplayName("acme", "web") != "Web" {
+		t.Errorf("DisplayName() = %q, want the mapping added in place", host.DisplayName("acme", "web"))
+	}
+	if _, org, repo, ok := Resolve(cfg, "Web"); !ok || org != "Acme" || repo != "web" {
+		t.Errorf("Resolve(Web) = %s/%s %v, want Acme/web", org, repo, ok)
+	}
+}
+
+func TestCurrent(t *testing.T) {
+	dir := t.TempDir()
+	gitConfig := `[remote "upstream"]
+	url = git@github.com:golang/go.git
+[remote "origin"]
+	url = git@github.acme.com:platform/api.git
+[remote "mirror"]
+	url = git@gitlab.com:platform/api.git
+`
+	if err := os.MkdirAll(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".git", "config"), []byte(gitConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &types.Config{GitHub: types.GitHubConfig{Hosts: []types.GitHubHostConfig{{Host: "github.acme.com"}}}}
+
+	host, org, repo, ok := Current(cfg, dir)
+	if !ok || host.Name != "github.acme.com" || org != "platform" || repo != "api" {
+		t.Errorf("Current() = %s %s/%s %v, want the origin remote on github.acme.com", host.Name, org, repo, ok)
+	}
+
+	cfg.GitHub.DefaultOrg, cfg.GitHub.DefaultRepo = "Acme", "web"
+	if host, org, repo, ok = Current(cfg, dir); !ok || host.Name != "github.com" || org != "Acme" || repo != "web" {
+		t.Errorf("Current() = %s %s/%s %v, want the configured default", host.Name, org, repo, ok)
+	}
+
+	if _, _, _, ok = Current(&types.Config{}, t.TempDir()); ok {
+		t.Error("Current() outside a repository without defaults should fail")
+	}
+}
+
+func TestHost_URL(t *testing.T) {
+	host, _ := Lookup(testConfig(), "git.example.com")
+	if got := host.URL("org", "repo", "pull", "1"); got != "https://code.example.com/org/repo/pull/1" {
+		t.Errorf("URL() = %q", got)
+	}
+}
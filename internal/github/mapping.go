@@ -0,0 +1,322 @@
+package github
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// regexMeta are the characters that make a mapping key a regular expression.
+// "." and "*" are left out since "." is common in repository names and "*"
+// on its own is a glob.
+const regexMeta = `()[]{}^$+?|\`
+
+var templateRefRegex = regexp.MustCompile(`\$(\$|\{\w+\}|\w+)`)
+
+// mapping is one entry of a host's mappings. A key is one of:
+//
+//   - an exact org/repo name
+//   - a whole-org alias, e.g. "companycam: CC" turns companycam/api into CC/api
+//   - a glob, where each "*" matches within one path segment and fills the
+//     matching "*" of the display name, e.g. "companycam/*: CC/*"
+//   - a regular expression whose groups are used as $1, $2, ... in the display
+//     name, e.g. "companycam/companycam-(.*): CC/$1"
+//
+// Patterns are matched against the whole org/repo, ignoring case.
+type mapping struct {
+	key     string
+	display string
+
+	// pattern is nil for an exact key
+	pattern *regexp.Regexp
+	// template is the display name in regexp.Expand form
+	template string
+	// keyTemplate is the org/repo in the same form, used to reverse the
+	// mapping; empty when the pattern can't be reversed
+	keyTemplate string
+}
+
+// ValidateMapping reports whether key and display form a usable mapping
+func ValidateMapping(key, display string) error {
+	_, err := parseMapping(key, display)
+	return err
+}
+
+func parseMapping(key, display string) (mapping, error) {
+	m := mapping{key: key, display: display}
+	if strings.TrimSpace(display) == "" {
+		return m, fmt.Errorf("%q maps to an empty name", key)
+	}
+	if key == "" || strings.ContainsAny(key, " \t\n") {
+		return m, fmt.Errorf("key %q must be in org/repo form", key)
+	}
+
+	switch {
+	case strings.ContainsAny(key, regexMeta):
+		pattern, err := regexp.Compile(`(?i)^(?:` + key + `)$`)
+		if err != nil {
+			return m, fmt.Errorf("key %q is not a valid regular expression: %v", key, err)
+		}
+		for _, ref := range templateRefs(display) {
+			n, err := strconv.Atoi(ref)
+			if err != nil || n > pattern.NumSubexp() {
+				return m, fmt.Errorf("%q refers to $%s, which %q does not capture", display, ref, key)
+			}
+		}
+		m.pattern = pattern
+		m.template = display
+		m.keyTemplate = regexKeyTemplate(key)
+
+	case strings.Contains(key, "*"):
+		org, repo, found := strings.Cut(key, "/")
+		if !found || org == "" || repo == "" || strings.Contains(repo, "/") {
+			return m, fmt.Errorf("key %q must be in org/repo form", key)
+		}
+		keyParts := strings.Split(key, "*")
+		displayParts := strings.Split(display, "*")
+		if len(displayParts) > len(keyParts) {
+			return m, fmt.Errorf("%q uses more \"*\" than %q", display, key)
+		}
+
+		quoted := make([]string, len(keyParts))
+		for i, part := range keyParts {
+			quoted[i] = regexp.QuoteMeta(part)
+		}
+		m.pattern = regexp.MustCompile(`(?i)^` + strings.Join(quoted, `([^/]+)`) + `$`)
+		m.template = globTemplate(displayParts)
+		m.keyTemplate = globTemplate(keyParts)
+
+	case !strings.Contains(key, "/"):
+		// Whole-org alias
+		if strings.Contains(display, "/") {
+			return m, fmt.Errorf("org alias %q must map to an org name, not %q", key, display)
+		}
+		m.pattern = regexp.MustCompile(`(?i)^` + regexp.QuoteMeta(key) + `/([^/]+)$`)
+		m.template = escapeTemplate(display) + "/${1}"
+		m.keyTemplate = escapeTemplate(key) + "/${1}"
+
+	default:
+		org, repo, _ := strings.Cut(key, "/")
+		if org == "" || repo == "" || strings.Contains(repo, "/") {
+			return m, fmt.Errorf("key %q must be in org/repo form", key)
+		}
+	}
+	return m, nil
+}
+
+// compileMappings parses mappings in order of precedence: exact keys first,
+// then patterns with longer keys before shorter ones. Ties are broken by key
+// so the order never depends on map iteration. Invalid entries, which config
+// validation reports, are skipped.
+func compileMappings(mappings map[string]string) []mapping {
+	compiled := make([]mapping, 0, len(mappings))
+	for key, display := range mappings {
+		if m, err := parseMapping(key, display); err == nil {
+			compiled = append(compiled, m)
+		}
+	}
+
+	sort.Slice(compiled, func(i, j int) bool {
+		a, b := compiled[i], compiled[j]
+		if (a.pattern == nil) != (b.pattern == nil) {
+			return a.pattern == nil
+		}
+		if len(a.key) != len(b.key) {
+			return len(a.key) > len(b.key)
+		}
+		return a.key < b.key
+	})
+	return compiled
+}
+
+// apply returns the display name for orgRepo if the mapping matches it
+func (m mapping) apply(orgRepo string) (string, bool) {
+	if m.pattern == nil {
+		return m.display, strings.EqualFold(m.key, orgRepo)
+	}
+
+	match := m.pattern.FindStringSubmatchIndex(orgRepo)
+	if match == nil {
+		return "", false
+	}
+	display := string(m.pattern.ExpandString(nil, m.template, orgRepo, match))
+	return display, display != ""
+}
+
+// reverse returns the org/repo the mapping turns into name
+func (m mapping) reverse(name string) (org, repo string, ok bool) {
+	orgRepo := m.key
+	if m.pattern != nil {
+		if orgRepo, ok = m.unexpand(name); !ok {
+			return "", "", false
+		}
+		// Guard against a guess the pattern doesn't actually map to name
+		if display, matched := m.apply(orgRepo); !matched || !strings.EqualFold(display, name) {
+			return "", "", false
+		}
+	} else if !strings.EqualFold(m.display, name) {
+		return "", "", false
+	}
+
+	org, repo, found := strings.Cut(orgRepo, "/")
+	if !found || org == "" || repo == "" {
+		return "", "", false
+	}
+	return org, repo, true
+}
+
+// unexpand recovers the groups of name from the display template and
+// substitutes them into the key template
+func (m mapping) unexpand(name string) (string, bool) {
+	if m.keyTemplate == "" {
+		return "", false
+	}
+
+	// Turn the display template into a pattern capturing each group it uses
+	var expr strings.Builder
+	expr.WriteString(`(?i)^`)
+	refs := make([]string, 0)
+	last := 0
+	for _, loc := range templateRefRegex.FindAllStringSubmatchIndex(m.template, -1) {
+		expr.WriteString(regexp.QuoteMeta(m.template[last:loc[0]]))
+		last = loc[1]
+		ref := strings.Trim(m.template[loc[2]:loc[3]], "{}")
+		if ref == "$" {
+			expr.WriteString(`\$`)
+			continue
+		}
+		expr.WriteString(`(.+)`)
+		refs = append(refs, ref)
+	}
+	expr.WriteString(regexp.QuoteMeta(m.template[last:]) + `$`)
+
+	reversed, err := regexp.Compile(expr.String())
+	if err != nil {
+		return "", false
+	}
+	match := reversed.FindStringSubmatch(name)
+	if match == nil {
+		return "", false
+	}
+
+	groups := make(map[string]string, len(refs))
+	for i, ref := range refs {
+		if seen, ok := groups[ref]; ok && seen != match[i+1] {
+			return "", false
+		}
+		groups[ref] = match[i+1]
+	}
+
+	ok := true
+	orgRepo := templateRefRegex.ReplaceAllStringFunc(m.keyTemplate, func(ref string) string {
+		ref = strings.Trim(ref[1:], "{}")
+		if ref == "$" {
+			return "$"
+		}
+		value, found := groups[ref]
+		if !found {
+			ok = false
+		}
+		return value
+	})
+	return orgRepo, ok
+}
+
+// displayOrg returns the org part of the display name when it is the same
+// for every repository the mapping matches
+func (m mapping) displayOrg() (string, bool) {
+	template := m.display
+	if m.pattern != nil {
+		template = m.template
+	}
+	org, _, found := strings.Cut(template, "/")
+	if !found || org == "" || strings.ContainsAny(org, "$*") {
+		return "", false
+	}
+	return org, true
+}
+
+// templateRefs lists the groups a display template refers to
+func templateRefs(template string) []string {
+	refs := make([]string, 0)
+	for _, match := range templateRefRegex.FindAllStringSubmatch(template, -1) {
+		if ref := strings.Trim(match[1], "{}"); ref != "$" {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// globTemplate joins the parts of a glob split on "*" with numbered groups
+func globTemplate(parts []string) string {
+	var b strings.Builder
+	for i, part := range parts {
+		if i > 0 {
+			fmt.Fprintf(&b, "${%d}", i)
+		}
+		b.WriteString(escapeTemplate(part))
+	}
+	return b.String()
+}
+
+func escapeTemplate(s string) string {
+	return strings.ReplaceAll(s, "$", "$$")
+}
+
+// regexKeyTemplate rewrites a regular expression key as an org/repo template
+// by replacing each top-level capturing group with its number. It returns ""
+// if anything other than literal text remains, since such a key can't be
+// reversed.
+func regexKeyTemplate(key string) string {
+	key = strings.TrimSuffix(strings.TrimPrefix(key, "^"), "$")
+
+	var b strings.Builder
+	group, depth := 0, 0
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case c == '\\':
+			if i+1 >= len(key) {
+				return ""
+			}
+			i++
+			if depth == 0 {
+				next := key[i]
+				if next >= 'a' && next <= 'z' || next >= 'A' && next <= 'Z' || next >= '0' && next <= '9' {
+					// A character class such as \d, not an escaped literal
+					return ""
+				}
+				b.WriteString(escapeTemplate(string(next)))
+			}
+		case c == '(':
+			capturing := !strings.HasPrefix(key[i:], "(?")
+			if capturing {
+				group++
+			}
+			if depth == 0 {
+				if !capturing {
+					return ""
+				}
+				fmt.Fprintf(&b, "${%d}", group)
+			}
+			depth++
+		case c == ')':
+			depth--
+			if depth < 0 {
+				return ""
+			}
+		case depth > 0:
+			// Inside a group, replaced as a whole
+		case strings.IndexByte(regexMeta+"*", c) >= 0:
+			return ""
+		default:
+			b.WriteByte(c)
+		}
+	}
+	if depth != 0 {
+		return ""
+	}
+	return b.String()
+}
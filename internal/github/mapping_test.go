@@ -0,0 +1,115 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+// patternHost has mappings of every kind, with keys lowercased as viper
+// leaves them
+func patternHost() Host {
+	return Host{
+		Name:        DefaultHost,
+		BaseURL:     "https://github.com",
+		DefaultOrg:  "CompanyCam",
+		DefaultRepo: "Company-Cam-API",
+		Mappings: map[string]string{
+			"companycam/company-cam-api":  "CompanyCam/API",
+			"companycam/companycam-(.*)":  "CC/$1",
+			"companycam/companycam-web-*": "Web/*",
+			"companycam/*":                "CC/*",
+			"acme":                        "A",
+			"(.*)/(.*)-legacy":            "Old $1/$2",
+		},
+	}
+}
+
+func TestHost_DisplayName(t *testing.T) {
+	tests := []struct {
+		name     string
+		org      string
+		repo     string
+		expected string
+	}{
+		{"Exact beats patterns", "CompanyCam", "Company-Cam-API", "CompanyCam/API"},
+		{"Regex keeps the case of the input", "CompanyCam", "companycam-Mobile", "CC/Mobile"},
+		{"Longer glob beats regex", "CompanyCam", "companycam-web-admin", "Web/admin"},
+		{"Longer regex beats shorter glob", "companycam", "companycam-ios", "CC/ios"},
+		{"Glob", "CompanyCam", "infra", "CC/infra"},
+		{"Whole-org alias", "Acme", "widgets", "A/widgets"},
+		{"Several groups", "Other", "tool-legacy", "Old Other/tool"},
+		{"No mapping", "someone", "else", "someone/else"},
+	}
+
+	host := patternHost()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := host.DisplayName(tt.org, tt.repo); got != tt.expected {
+				t.Errorf("DisplayName(%q, %q) = %q, want %q", tt.org, tt.repo, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolve_Patterns(t *testing.T) {
+	host := patternHost()
+	cfg := &types.Config{GitHub: types.GitHubConfig{
+		DefaultOrg:  host.DefaultOrg,
+		DefaultRepo: host.DefaultRepo,
+		Mappings:    host.Mappings,
+	}}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		ok       bool
+	}{
+		{"Exact display name", "CompanyCam/API", "CompanyCam/Company-Cam-API", true},
+		{"Regex display name", "CC/mobile", "CompanyCam/companycam-mobile", true},
+		{"Glob display name", "Web/admin", "CompanyCam/companycam-web-admin", true},
+		{"Whole-org alias", "A/widgets", "acme/widgets", true},
+		{"Bare repository name", "API", "CompanyCam/Company-Cam-API", true},
+		{"Unmapped org/repo", "someone/else", "someone/else", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, org, repo, ok := Resolve(cfg, tt.input)
+			if ok != tt.ok {
+				t.Fatalf("Resolve(%q) ok = %v, want %v", tt.input, ok, tt.ok)
+			}
+			if got := org + "/" + repo; got != tt.expected {
+				t.Errorf("Resolve(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateMapping(t *testing.T) {
+	tests := []struct {
+		key     string
+		display string
+		valid   bool
+	}{
+		{"acme/api", "API", true},
+		{"acme", "A", true},
+		{"acme/*", "A/*", true},
+		{"acme/acme-(.*)", "A/$1", true},
+		{"acme/api/v2", "API", false},
+		{"acme", "A/B", false},
+		{"acme/*", "A/*/*", false},
+		{"acme/(.*", "A", false},
+		{"acme/acme-(.*)", "A/$2", false},
+		{"acme/api", " ", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+" "+tt.display, func(t *testing.T) {
+			if err := ValidateMapping(tt.key, tt.display); (err == nil) != tt.valid {
+				t.Errorf("ValidateMapping(%q, %q) = %v, want valid %v", tt.key, tt.display, err, tt.valid)
+			}
+		})
+	}
+}
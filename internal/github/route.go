@@ -0,0 +1,302 @@
+package github
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Route kinds, stored as the "type" metadata of a parsed GitHub URL. The
+// repository root has no kind.
+const (
+	KindPull        = "pull"
+	KindPulls       = "pulls"
+	KindIssues      = "issues"
+	KindCommit      = "commit"
+	KindCommits     = "commits"
+	KindBlob        = "blob"
+	KindBlame       = "blame"
+	KindTree        = "tree"
+	KindCompare     = "compare"
+	KindRelease     = "release"
+	KindReleases    = "releases"
+	KindTags        = "tags"
+	KindActions     = "actions"
+	KindRun         = "run"
+	KindWorkflow    = "workflow"
+	KindDiscussion  = "discussion"
+	KindDiscussions = "discussions"
+	KindWiki        = "wiki"
+)
+
+// Anchors within an issue, pull request or discussion
+const (
+	AnchorComment       = "comment"
+	AnchorReview        = "review"
+	AnchorReviewComment = "review_comment"
+	AnchorCommit        = "commit"
+)
+
+// anchorFragments map URL fragment prefixes to anchors. Review comments are
+// #discussion_r<id> on the conversation and #r<id> on the files view.
+var anchorFragments = []struct {
+	prefix string
+	anchor string
+}{
+	{"issuecomment-", AnchorComment},
+	{"discussioncomment-", AnchorComment},
+	{"pullrequestreview-", AnchorReview},
+	{"discussion_r", AnchorReviewComment},
+	{"r", AnchorReviewComment},
+}
+
+var (
+	numberRegex = regexp.MustCompile(`^\d+$`)
+	// lineRegex matches line anchors such as L10, L10-L20 or L10C5-L12C2
+	lineRegex = regexp.MustCompile(`^L\d+(C\d+)?(-L\d+(C\d+)?)?$`)
+)
+
+// reservedOwners are first path segments that are GitHub pages rather than
+// an organization or user
+var reservedOwners = map[string]bool{
+	"about": true, "apps": true, "codespaces": true, "collections": true,
+	"enterprises": true, "explore": true, "features": true, "login": true,
+	"marketplace": true, "new": true, "notifications": true, "orgs": true,
+	"organizations": true, "pulls": true, "issues": true, "search": true,
+	"settings": true, "sponsors": true, "topics": true, "users": true,
+}
+
+// Route is what a GitHub URL points at within a repository
+type Route struct {
+	Org  string
+	Repo string
+	Kind string
+	// Number is the issue, pull request or discussion number, or the SHA of
+	// a commit
+	Number string
+	// Ref is the branch, tag or SHA of a file, tree or commit list
+	Ref   string
+	Path  string
+	Lines string
+	// Base and Head are the two sides of a comparison; Base may be empty
+	Base string
+	Head string
+	// Tag is the release tag, empty for the latest release
+	Tag   string
+	Asset string
+	// RunID, JobID and Attempt identify an Actions run; Workflow its file
+	RunID    string
+	JobID    string
+	Attempt  string
+	Workflow string
+	// Page is the wiki page name
+	Page string
+	// Anchor is a comment, review, review comment or commit within an issue,
+	// pull request or discussion, identified by AnchorID
+	Anchor   string
+	AnchorID string
+}
+
+// ParseRoute classifies a GitHub URL path. Path segments are unescaped
+// individually, so a branch written as feature%2Flogin stays one ref. It
+// returns false when the path doesn't name a repository.
+func ParseRoute(u *url.URL) (Route, bool) {
+	segments := splitPath(u.EscapedPath())
+	if len(segments) < 2 || reservedOwners[strings.ToLower(segments[0])] {
+		return Route{}, false
+	}
+
+	route := Route{Org: segments[0], Repo: segments[1]}
+	rest := segments[2:]
+	if len(rest) == 0 {
+		return route, true
+	}
+
+	arg := func(i int) string {
+		if i < len(rest) {
+			return rest[i]
+		}
+		return ""
+	}
+	tail := func(i int) string {
+		if i < len(rest) {
+			return strings.Join(rest[i:], "/")
+		}
+		return ""
+	}
+
+	switch rest[0] {
+	case "pull":
+		if numberRegex.MatchString(arg(1)) {
+			route.Kind = KindPull
+			route.Number = arg(1)
+			if arg(2) == "commits" && arg(3) != "" {
+				route.Anchor = AnchorCommit
+				route.AnchorID = arg(3)
+			}
+		}
+	case "pulls":
+		route.Kind = KindPulls
+	case "issues":
+		route.Kind = KindIssues
+		if numberRegex.MatchString(arg(1)) {
+			route.Number = arg(1)
+		}
+	case "commit":
+		if arg(1) != "" {
+			route.Kind = KindCommit
+			route.Number = arg(1)
+		}
+	case "commits":
+		route.Kind = KindCommits
+		route.Ref = tail(1)
+	case "blob", "blame":
+		if arg(1) != "" {
+			route.Kind = rest[0]
+			route.Ref = arg(1)
+			route.Path = tail(2)
+			if lineRegex.MatchString(u.Fragment) {
+				route.Lines = u.Fragment
+			}
+		}
+	case "tree":
+		if arg(1) != "" {
+			route.Kind = KindTree
+			route.Ref = arg(1)
+			route.Path = tail(2)
+		}
+	case "compare":
+		spec := tail(1)
+		if spec != "" {
+			route.Kind = KindCompare
+			route.Base, route.Head = splitCompare(spec)
+		}
+	case "releases":
+		switch arg(1) {
+		case "":
+			route.Kind = KindReleases
+		case "tag":
+			route.Kind = KindRelease
+			route.Tag = tail(2)
+		case "latest":
+			route.Kind = KindRelease
+		case "download":
+			route.Kind = KindRelease
+			route.Tag = arg(2)
+			route.Asset = tail(3)
+		}
+	case "tags":
+		route.Kind = KindTags
+	case "actions":
+		route.Kind = KindActions
+		switch arg(1) {
+		case "runs":
+			if numberRegex.MatchString(arg(2)) {
+				route.Kind = KindRun
+				route.RunID = arg(2)
+				switch arg(3) {
+				case "job", "jobs":
+					route.JobID = arg(4)
+				case "attempts":
+					route.Attempt = arg(4)
+				}
+			}
+		case "workflows":
+			if arg(2) != "" {
+				route.Kind = KindWorkflow
+				route.Workflow = arg(2)
+			}
+		}
+	case "discussions":
+		route.Kind = KindDiscussions
+		if numberRegex.MatchString(arg(1)) {
+			route.Kind = KindDiscussion
+			route.Number = arg(1)
+		}
+	case "wiki":
+		route.Kind = KindWiki
+		route.Page = tail(1)
+	}
+
+	if route.Number != "" && route.Kind != KindCommit && route.Anchor == "" {
+		route.Anchor, route.AnchorID = parseAnchor(u.Fragment)
+	}
+
+	return route, true
+}
+
+// parseAnchor recognises comment and review fragments such as
+// issuecomment-123
+func parseAnchor(fragment string) (anchor, id string) {
+	for _, candidate := range anchorFragments {
+		if rest, ok := strings.CutPrefix(fragment, candidate.prefix); ok && numberRegex.MatchString(rest) {
+			return candidate.anchor, rest
+		}
+	}
+	return "", ""
+}
+
+// anchorKeys name the metadata holding each anchor's ID
+var anchorKeys = map[string]string{
+	AnchorComment:       "comment_id",
+	AnchorReview:        "review_id",
+	AnchorReviewComment: "review_comment_id",
+	AnchorCommit:        "commit",
+}
+
+// Metadata returns the route's fields as parse metadata, omitting empty ones
+func (r Route) Metadata() map[string]string {
+	fields := map[string]string{
+		"org":      r.Org,
+		"repo":     r.Repo,
+		"type":     r.Kind,
+		"number":   r.Number,
+		"ref":      r.Ref,
+		"path":     r.Path,
+		"lines":    r.Lines,
+		"base":     r.Base,
+		"head":     r.Head,
+		"tag":      r.Tag,
+		"asset":    r.Asset,
+		"run_id":   r.RunID,
+		"job_id":   r.JobID,
+		"attempt":  r.Attempt,
+		"workflow": r.Workflow,
+		"page":     r.Page,
+		"anchor":   r.Anchor,
+	}
+	if key, ok := anchorKeys[r.Anchor]; ok {
+		fields[key] = r.AnchorID
+	}
+	for key, value := range fields {
+		if value == "" {
+			delete(fields, key)
+		}
+	}
+	return fields
+}
+
+// splitPath splits an escaped URL path into unescaped segments
+func splitPath(escaped string) []string {
+	segments := make([]string, 0)
+	for _, segment := range strings.Split(escaped, "/") {
+		if segment == "" {
+			continue
+		}
+		if unescaped, err := url.PathUnescape(segment); err == nil {
+			segment = unescaped
+		}
+		segments = append(segments, segment)
+	}
+	return segments
+}
+
+// splitCompare splits "base...head" or "base..head"; a lone ref is the head
+func splitCompare(spec string) (base, head string) {
+	for _, sep := range []string{"...", ".."} {
+		if before, after, found := strings.Cut(spec, sep); found {
+			return before, after
+		}
+	}
+	return "", spec
+}
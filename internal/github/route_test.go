@@ -0,0 +1,92 @@
+package github
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestParseRoute(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Route
+		ok       bool
+	}{
+		{"Repository", "https://github.com/acme/api", Route{Org: "acme", Repo: "api"}, true},
+		{"Pull request subpage", "https://github.com/acme/api/pull/12/files", Route{Org: "acme", Repo: "api", Kind: KindPull, Number: "12"}, true},
+		{"Issue list", "https://github.com/acme/api/issues?q=is%3Aopen", Route{Org: "acme", Repo: "api", Kind: KindIssues}, true},
+		{"Blob with lines", "https://github.com/acme/api/blob/main/cmd/root.go#L10-L20", Route{Org: "acme", Repo: "api", Kind: KindBlob, Ref: "main", Path: "cmd/root.go", Lines: "L10-L20"}, true},
+		{"Blob ignores other fragments", "https://github.com/acme/api/blob/main/README.md#usage", Route{Org: "acme", Repo: "api", Kind: KindBlob, Ref: "main", Path: "README.md"}, true},
+		{"Encoded ref and path", "https://github.com/acme/api/blame/feature%2Fsso/a%20b.go#L3", Route{Org: "acme", Repo: "api", Kind: KindBlame, Ref: "feature/sso", Path: "a b.go", Lines: "L3"}, true},
+		{"Tree root", "https://github.com/acme/api/tree/v2", Route{Org: "acme", Repo: "api", Kind: KindTree, Ref: "v2"}, true},
+		{"Commits on branch", "https://github.com/acme/api/commits/release/2.0", Route{Org: "acme", Repo: "api", Kind: KindCommits, Ref: "release/2.0"}, true},
+		{"Compare two dots", "https://github.com/acme/api/compare/main..fix/login", Route{Org: "acme", Repo: "api", Kind: KindCompare, Base: "main", Head: "fix/login"}, true},
+		{"Compare head only", "https://github.com/acme/api/compare/feature", Route{Org: "acme", Repo: "api", Kind: KindCompare, Head: "feature"}, true},
+		{"Latest release", "https://github.com/acme/api/releases/latest", Route{Org: "acme", Repo: "api", Kind: KindRelease}, true},
+		{"Release asset", "https://github.com/acme/api/releases/download/v1.0/api.tar.gz", Route{Org: "acme", Repo: "api", Kind: KindRelease, Tag: "v1.0", Asset: "api.tar.gz"}, true},
+		{"Tags", "https://github.com/acme/api/tags", Route{Org: "acme", Repo: "api", Kind: KindTags}, true},
+		{"Actions", "https://github.com/acme/api/actions", Route{Org: "acme", Repo: "api", Kind: KindActions}, true},
+		{"Run attempt", "https://github.com/acme/api/actions/runs/99/attempts/2", Route{Org: "acme", Repo: "api", Kind: KindRun, RunID: "99", Attempt: "2"}, true},
+		{"Discussions", "https://github.com/acme/api/discussions", Route{Org: "acme", Repo: "api", Kind: KindDiscussions}, true},
+		{"Wiki home", "https://github.com/acme/api/wiki", Route{Org: "acme", Repo: "api", Kind: KindWiki}, true},
+		{"Issue comment", "https://github.com/acme/api/issues/7#issuecomment-123", Route{Org: "acme", Repo: "api", Kind: KindIssues, Number: "7", Anchor: AnchorComment, AnchorID: "123"}, true},
+		{"Review", "https://github.com/acme/api/pull/12#pullrequestreview-789", Route{Org: "acme", Repo: "api", Kind: KindPull, Number: "12", Anchor: AnchorReview, AnchorID: "789"}, true},
+		{"Review comment", "https://github.com/acme/api/pull/12#discussion_r456", Route{Org: "acme", Repo: "api", Kind: KindPull, Number: "12", Anchor: AnchorReviewComment, AnchorID: "456"}, true},
+		{"Review comment on files", "https://github.com/acme/api/pull/12/files#r456", Route{Org: "acme", Repo: "api", Kind: KindPull, Number: "12", Anchor: AnchorReviewComment, AnchorID: "456"}, true},
+		{"Commit in pull request", "https://github.com/acme/api/pull/12/commits/aa062a602a02d33f", Route{Org: "acme", Repo: "api", Kind: KindPull, Number: "12", Anchor: AnchorCommit, AnchorID: "aa062a602a02d33f"}, true},
+		{"Discussion comment", "https://github.com/acme/api/discussions/42#discussioncomment-9", Route{Org: "acme", Repo: "api", Kind: KindDiscussion, Number: "42", Anchor: AnchorComment, AnchorID: "9"}, true},
+		{"Other fragment", "https://github.com/acme/api/pull/12#partial-pull-merging", Route{Org: "acme", Repo: "api", Kind: KindPull, Number: "12"}, true},
+		{"Unknown page keeps repository", "https://github.com/acme/api/settings", Route{Org: "acme", Repo: "api"}, true},
+		{"Organization page", "https://github.com/orgs/acme/teams", Route{}, false},
+		{"Single segment", "https://github.com/acme", Route{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("url.Parse() error = %v", err)
+			}
+			route, ok := ParseRoute(u)
+			if ok != tt.ok {
+				t.Fatalf("ParseRoute() ok = %v, want %v", ok, tt.ok)
+			}
+			if !reflect.DeepEqual(route, tt.expected) {
+				t.Errorf("ParseRoute() = %+v, want %+v", route, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRoute_Metadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		route    Route
+		expected map[string]string
+	}{
+		{
+			"Actions job",
+			Route{Org: "acme", Repo: "api", Kind: KindRun, RunID: "1", JobID: "2"},
+			map[string]string{"org": "acme", "repo": "api", "type": "run", "run_id": "1", "job_id": "2"},
+		},
+		{
+			"Review comment",
+			Route{Org: "acme", Repo: "api", Kind: KindPull, Number: "12", Anchor: AnchorReviewComment, AnchorID: "456"},
+			map[string]string{"org": "acme", "repo": "api", "type": "pull", "number": "12", "anchor": "review_comment", "review_comment_id": "456"},
+		},
+		{
+			"Commit in pull request",
+			Route{Org: "acme", Repo: "api", Kind: KindPull, Number: "12", Anchor: AnchorCommit, AnchorID: "abc"},
+			map[string]string{"org": "acme", "repo": "api", "type": "pull", "number": "12", "anchor": "commit", "commit": "abc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.route.Metadata(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Metadata() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
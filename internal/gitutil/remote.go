@@ -0,0 +1,154 @@
+package gitutil
+
+import (
+	"bufio"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Remote is a git remote URL broken into its forge host, owner and repository
+type Remote struct {
+	Name string
+	URL  string
+	Host string
+	Org  string
+	Repo string
+}
+
+// scpLikeRegex matches scp-style remotes such as git@github.com:org/repo.git
+var scpLikeRegex = regexp.MustCompile(`^(?:[A-Za-z0-9._-]+@)?([A-Za-z0-9.-]+):([^/][^:]*)$`)
+
+// ParseRemoteURL parses scp-style (git@host:org/repo.git) and URL-style
+// (ssh://, git://, https://) remotes. Nested groups such as GitLab
+// subgroups are kept in Org, so Repo is always the final path segment.
+func ParseRemoteURL(raw string) (Remote, bool) {
+	raw = strings.TrimSpace(raw)
+	remote := Remote{URL: raw}
+
+	var host, path string
+	if strings.Contains(raw, "://") {
+		u, err := url.Parse(raw)
+		if err != nil || u.Hostname() == "" {
+			return remote, false
+		}
+		host = u.Hostname()
+		path = u.Path
+	} else {
+		matches := scpLikeRegex.FindStringSubmatch(raw)
+		if matches == nil {
+			return remote, false
+		}
+		host = matches[1]
+		path = matches[2]
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	path = strings.TrimSuffix(path, "/")
+	idx := strings.LastIndex(path, "/")
+	if idx <= 0 || idx == len(path)-1 {
+		return remote, false
+	}
+
+	remote.Host = strings.ToLower(host)
+	remote.Org = path[:idx]
+	remote.Repo = path[idx+1:]
+	return remote, true
+}
+
+// FindRemotes returns the remotes of the git repository containing dir,
+// read from its config file. It returns nil if dir is not inside a repository.
+func FindRemotes(dir string) []Remote {
+	configPath := findGitConfig(dir)
+	if configPath == "" {
+		return nil
+	}
+
+	file, err := os.Open(configPath)
+	if err != nil {
+		return nil
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	sectionRegex := regexp.MustCompile(`^\[remote\s+"([^"]+)"\]$`)
+	remotes := make([]Remote, 0)
+	var current string
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			current = ""
+			if matches := sectionRegex.FindStringSubmatch(line); matches != nil {
+				current = matches[1]
+			}
+			continue
+		}
+		if current == "" {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found || strings.TrimSpace(key) != "url" {
+			continue
+		}
+		if remote, ok := ParseRemoteURL(value); ok {
+			remote.Name = current
+			remotes = append(remotes, remote)
+		}
+	}
+
+	return remotes
+}
+
+// findGitConfig walks up from dir to locate the repository's config file,
+// following the "gitdir:" indirection used by worktrees and submodules
+func findGitConfig(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+
+	for {
+		gitPath := filepath.Join(dir, ".git")
+		info, err := os.Stat(gitPath)
+		if err == nil {
+			if info.IsDir() {
+				return filepath.Join(gitPath, "config")
+			}
+			return gitDirConfig(dir, gitPath)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func gitDirConfig(dir, gitFile string) string {
+	data, err := os.ReadFile(gitFile)
+	if err != nil {
+		return ""
+	}
+
+	gitDir := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(string(data)), "gitdir:"))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(dir, gitDir)
+	}
+
+	// Linked worktrees keep their config in the common dir
+	if common, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir := strings.TrimSpace(string(common))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+		return filepath.Join(commonDir, "config")
+	}
+	return filepath.Join(gitDir, "config")
+}
@@ -0,0 +1,108 @@
+package gitutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseRemoteURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Remote
+		ok       bool
+	}{
+		{
+			name:     "scp-style",
+			input:    "git@github.com:CompanyCam/Company-Cam-API.git",
+			expected: Remote{Host: "github.com", Org: "CompanyCam", Repo: "Company-Cam-API"},
+			ok:       true,
+		},
+		{
+			name:     "ssh URL with port",
+			input:    "ssh://git@GitHub.com:22/CompanyCam/Company-Cam-API.git",
+			expected: Remote{Host: "github.com", Org: "CompanyCam", Repo: "Company-Cam-API"},
+			ok:       true,
+		},
+		{
+			name:     "https URL without suffix",
+			input:    "https://github.com/ErebusBat/markdown-tool",
+			expected: Remote{Host: "github.com", Org: "ErebusBat", Repo: "markdown-tool"},
+			ok:       true,
+		},
+		{
+			name:     "GitLab subgroup",
+			input:    "git@gitlab.com:group/subgroup/project.git",
+			expected: Remote{Host: "gitlab.com", Org: "group/subgroup", Repo: "project"},
+			ok:       true,
+		},
+		{
+			name:  "Local path",
+			input: "/srv/git/project.git",
+			ok:    false,
+		},
+		{
+			name:  "Missing repository",
+			input: "https://github.com/CompanyCam",
+			ok:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote, ok := ParseRemoteURL(tt.input)
+			if ok != tt.ok {
+				t.Fatalf("ParseRemoteURL(%q) ok = %v, want %v", tt.input, ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if remote.Host != tt.expected.Host || remote.Org != tt.expected.Org || remote.Repo != tt.expected.Repo {
+				t.Errorf("ParseRemoteURL(%q) = %+v, want %+v", tt.input, remote, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFindRemotes(t *testing.T) {
+	repo := t.TempDir()
+	gitDir := filepath.Join(repo, ".git")
+	if err := os.MkdirAll(gitDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	gitConfig := `[core]
+	bare = false
+[remote "origin"]
+	url = git@github.com:CompanyCam/Company-Cam-API.git
+	fetch = +refs/heads/*:refs/remotes/origin/*
+[branch "main"]
+	remote = origin
+[remote "upstream"]
+	url = https://github.com/upstream-org/api.git
+`
+	if err := os.WriteFile(filepath.Join(gitDir, "config"), []byte(gitConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	nested := filepath.Join(repo, "cmd", "sub")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	remotes := FindRemotes(nested)
+	if len(remotes) != 2 {
+		t.Fatalf("FindRemotes() returned %d remotes, want 2", len(remotes))
+	}
+	if remotes[0].Name != "origin" || remotes[0].Org != "CompanyCam" {
+		t.Errorf("remotes[0] = %+v, want origin CompanyCam", remotes[0])
+	}
+	if remotes[1].Name != "upstream" || remotes[1].Repo != "api" {
+		t.Errorf("remotes[1] = %+v, want upstream api", remotes[1])
+	}
+
+	if got := FindRemotes(t.TempDir()); got != nil {
+		t.Errorf("FindRemotes() outside a repository = %v, want nil", got)
+	}
+}
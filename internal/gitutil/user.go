@@ -0,0 +1,76 @@
+package gitutil
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GitHubUser returns the github.user setting from the user's global git
+// config (~/.gitconfig or $XDG_CONFIG_HOME/git/config), or "" if unset
+func GitHubUser() string {
+	user := ""
+	for _, path := range globalConfigPaths() {
+		// Later files take precedence, as with git itself
+		if value := readConfigValue(path, "github", "user"); value != "" {
+			user = value
+		}
+	}
+	return user
+}
+
+// globalConfigPaths lists the global git config files in the order git reads them
+func globalConfigPaths() []string {
+	paths := make([]string, 0, 2)
+
+	xdg := os.Getenv("XDG_CONFIG_HOME")
+	home, err := os.UserHomeDir()
+	if xdg == "" && err == nil {
+		xdg = filepath.Join(home, ".config")
+	}
+	if xdg != "" {
+		paths = append(paths, filepath.Join(xdg, "git", "config"))
+	}
+	if err == nil {
+		paths = append(paths, filepath.Join(home, ".gitconfig"))
+	}
+	return paths
+}
+
+// readConfigValue returns the last value of key in a plain [section] of the
+// git config file at path. Section and key names are case-insensitive.
+func readConfigValue(path, section, key string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	value := ""
+	inSection := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			name := strings.TrimSpace(strings.Trim(line, "[]"))
+			inSection = strings.EqualFold(name, section)
+			continue
+		}
+		if !inSection {
+			continue
+		}
+
+		k, v, found := strings.Cut(line, "=")
+		if found && strings.EqualFold(strings.TrimSpace(k), key) {
+			value = strings.Trim(strings.TrimSpace(v), `"`)
+		}
+	}
+
+	return value
+}
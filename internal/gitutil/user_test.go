@@ -0,0 +1,44 @@
+package gitutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGitHubUser(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg"))
+
+	if got := GitHubUser(); got != "" {
+		t.Errorf("GitHubUser() without config = %q, want empty", got)
+	}
+
+	xdgConfig := filepath.Join(home, "xdg", "git", "config")
+	if err := os.MkdirAll(filepath.Dir(xdgConfig), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(xdgConfig, []byte("[GitHub]\n\tUser = xdg-user\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := GitHubUser(); got != "xdg-user" {
+		t.Errorf("GitHubUser() = %q, want xdg-user", got)
+	}
+
+	gitConfig := `[user]
+	name = Someone
+	user = not-this-one
+# [github] in a comment
+[github]
+	user = "octocat"
+[remote "origin"]
+	user = nor-this
+`
+	if err := os.WriteFile(filepath.Join(home, ".gitconfig"), []byte(gitConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := GitHubUser(); got != "octocat" {
+		t.Errorf("GitHubUser() = %q, want ~/.gitconfig to win with octocat", got)
+	}
+}
@@ -0,0 +1,331 @@
+package history
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+const (
+	// DefaultMaxEntries is the number of entries kept when none is configured
+	DefaultMaxEntries = 500
+	// DefaultMaxAge is how long entries are kept when no age is configured
+	DefaultMaxAge = 30 * 24 * time.Hour
+
+	fileName      = "history.jsonl"
+	lockName      = "history.lock"
+	previewLength = 80
+)
+
+// Entry is one recorded transformation
+type Entry struct {
+	Time      time.Time `json:"time"`
+	InputHash string    `json:"input_hash"`
+	Input     string    `json:"input,omitempty"`
+	Type      string    `json:"type"`
+	Output    string    `json:"output"`
+	Profile   string    `json:"profile,omitempty"`
+}
+
+// Options configures a Store
+type Options struct {
+	MaxEntries int
+	MaxAge     time.Duration
+	// HideInput records only a hash of the input, not a preview. The
+	// output is still stored, since history copy and search need it, and
+	// for a link it usually contains the input URL.
+	HideInput bool
+}
+
+// Store is an append-only log of transformations in a JSON Lines file.
+// Entries beyond the retention limits are dropped when new ones are added.
+// A nil *Store records nothing.
+type Store struct {
+	path string
+	opts Options
+	mu   sync.Mutex
+
+	// now is replaced in tests
+	now func() time.Time
+}
+
+// New returns a Store keeping its log in dir
+func New(dir string, opts Options) *Store {
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = DefaultMaxEntries
+	}
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = DefaultMaxAge
+	}
+	return &Store{
+		path: filepath.Join(dir, fileName),
+		opts: opts,
+		now:  time.Now,
+	}
+}
+
+// FromConfig returns the Store described by cfg, or nil when history is
+// disabled or has no directory
+func FromConfig(cfg *types.Config) *Store {
+	if cfg == nil || cfg.History.Disabled || cfg.History.Dir == "" {
+		return nil
+	}
+
+	return New(cfg.History.Dir, Options{
+		MaxEntries: cfg.History.MaxEntries,
+		MaxAge:     cfg.History.MaxAge,
+		HideInput:  cfg.History.HideInput,
+	})
+}
+
+// DefaultDir returns the history directory under the user's XDG data dir
+func DefaultDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "markdown-tool"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "markdown-tool"), nil
+}
+
+// Path returns the log file, or "" for a nil Store
+func (s *Store) Path() string {
+	if s == nil {
+		return ""
+	}
+	return s.path
+}
+
+// Record appends a transformation to the log and applies the retention limits
+func (s *Store) Record(input string, contentType types.ContentType, output, profile string) error {
+	if s == nil {
+		return nil
+	}
+
+	sum := sha256.Sum256([]byte(input))
+	entry := Entry{
+		Time:      s.now().UTC(),
+		InputHash: hex.EncodeToString(sum[:]),
+		Type:      contentType.String(),
+		Output:    output,
+		Profile:   profile,
+	}
+	if !s.opts.HideInput {
+		entry.Input = Preview(input)
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(line, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.prune()
+}
+
+// Entries returns the retained entries, newest first
+func (s *Store) Entries() ([]Entry, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	entries = s.retained(entries)
+
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, nil
+}
+
+// Search returns the retained entries, newest first, whose input preview,
+// output, content type or profile contain term, ignoring case
+func (s *Store) Search(term string) ([]Entry, error) {
+	entries, err := s.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	term = strings.ToLower(term)
+	matches := make([]Entry, 0)
+	for _, entry := range entries {
+		fields := []string{entry.Input, entry.Output, entry.Type, entry.Profile}
+		for _, field := range fields {
+			if strings.Contains(strings.ToLower(field), term) {
+				matches = append(matches, entry)
+				break
+			}
+		}
+	}
+	return matches, nil
+}
+
+// Clear removes the log
+func (s *Store) Clear() error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// lock takes an exclusive lock shared with other processes using the same
+// log, so an append can't land between prune reading the log and replacing
+// it. The lock lives in a file of its own because prune replaces the log.
+func (s *Store) lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+	file, err := os.OpenFile(filepath.Join(filepath.Dir(s.path), lockName), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(file); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to lock history: %w", err)
+	}
+	return func() {
+		_ = unlockFile(file)
+		_ = file.Close()
+	}, nil
+}
+
+// read returns every entry in the log, oldest first. Lines that can't be
+// decoded, such as one cut short by a crash, are skipped.
+func (s *Store) read() ([]Entry, error) {
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	entries := make([]Entry, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// retained drops entries older than MaxAge and all but the newest MaxEntries
+func (s *Store) retained(entries []Entry) []Entry {
+	cutoff := s.now().Add(-s.opts.MaxAge)
+	kept := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Time.After(cutoff) {
+			kept = append(kept, entry)
+		}
+	}
+	if len(kept) > s.opts.MaxEntries {
+		kept = kept[len(kept)-s.opts.MaxEntries:]
+	}
+	return kept
+}
+
+// prune rewrites the log without the entries outside the retention limits.
+// The log is replaced atomically so a reader never sees a partial file. The
+// caller holds the lock.
+func (s *Store) prune() error {
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+	kept := s.retained(entries)
+	if len(kept) == len(entries) {
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), fileName+".*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	writer := bufio.NewWriter(tmp)
+	encoder := json.NewEncoder(writer)
+	for _, entry := range kept {
+		if err := encoder.Encode(entry); err != nil {
+			_ = tmp.Close()
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// Preview shortens input to a single line for display
+func Preview(input string) string {
+	preview := strings.Join(strings.Fields(input), " ")
+	runes := []rune(preview)
+	if len(runes) > previewLength {
+		preview = string(runes[:previewLength-1]) + "…"
+	}
+	return preview
+}
@@ -0,0 +1,180 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+func TestStore_RecordEntries(t *testing.T) {
+	s := New(t.TempDir(), Options{})
+
+	if err := s.Record("https://github.com/a/b/pull/1", types.ContentTypeGitHubURL, "[a/b#1](https://github.com/a/b/pull/1)", "work"); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := s.Record("PROJ-1", types.ContentTypeJIRAKey, "[PROJ-1](https://x.atlassian.net/browse/PROJ-1)", ""); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	entries, err := s.Entries()
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Entries() returned %d entries, want 2", len(entries))
+	}
+	if entries[0].Input != "PROJ-1" || entries[0].Type != "jira_key" {
+		t.Errorf("newest entry = %+v, want PROJ-1 jira_key", entries[0])
+	}
+	if entries[1].Profile != "work" || entries[1].Type != "github_url" {
+		t.Errorf("oldest entry = %+v, want profile work github_url", entries[1])
+	}
+
+	info, err := os.Stat(s.Path())
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("log permissions = %o, want 600", perm)
+	}
+}
+
+func TestStore_HideInput(t *testing.T) {
+	s := New(t.TempDir(), Options{HideInput: true})
+
+	if err := s.Record("secret input", types.ContentTypeUnknown, "out", ""); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	data, err := os.ReadFile(s.Path())
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("log contains the input: %s", data)
+	}
+
+	entries, _ := s.Entries()
+	if len(entries) != 1 || entries[0].InputHash == "" {
+		t.Errorf("Entries() = %+v, want one entry with an input hash", entries)
+	}
+}
+
+func TestStore_Retention(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options
+		advance  time.Duration
+		expected []string
+	}{
+		{"Keeps newest entries", Options{MaxEntries: 2}, time.Minute, []string{"c", "b"}},
+		{"Drops old entries", Options{MaxAge: 150 * time.Minute}, time.Hour, []string{"c", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(t.TempDir(), tt.opts)
+			now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			s.now = func() time.Time { return now }
+
+			for _, input := range []string{"a", "b", "c"} {
+				if err := s.Record(input, types.ContentTypeUnknown, input, ""); err != nil {
+					t.Fatalf("Record() error = %v", err)
+				}
+				now = now.Add(tt.advance)
+			}
+
+			entries, err := s.Entries()
+			if err != nil {
+				t.Fatalf("Entries() error = %v", err)
+			}
+			got := make([]string, 0, len(entries))
+			for _, entry := range entries {
+				got = append(got, entry.Input)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Entries() = %v, want %v", got, tt.expected)
+			}
+
+			data, _ := os.ReadFile(s.Path())
+			if lines := strings.Count(string(data), "\n"); lines > len(tt.expected)+1 {
+				t.Errorf("log has %d lines after pruning", lines)
+			}
+		})
+	}
+}
+
+func TestStore_ConcurrentPruneKeepsAppends(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("history is only locked across processes where flock is available")
+	}
+	dir := t.TempDir()
+	now := time.Now()
+
+	// Separate Stores stand in for separate processes. Entries written with a
+	// clock in the past are pruned by the others, so every Record by a
+	// current Store rewrites the log while appends are in flight.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s := New(dir, Options{})
+			if i%2 == 1 {
+				s.now = func() time.Time { return now.Add(-60 * 24 * time.Hour) }
+			}
+			for j := 0; j < 20; j++ {
+				_ = s.Record(fmt.Sprintf("writer %d entry %d", i, j), types.ContentTypeUnknown, "out", "")
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	entries, err := New(dir, Options{}).Entries()
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	if len(entries) != 80 {
+		t.Errorf("Entries() returned %d entries, want all 80 current ones", len(entries))
+	}
+}
+
+func TestStore_SearchClear(t *testing.T) {
+	s := New(t.TempDir(), Options{})
+	_ = s.Record("https://example.com/Page", types.ContentTypeURL, "[Page](https://example.com/Page)", "")
+	_ = s.Record("PROJ-7", types.ContentTypeJIRAKey, "[PROJ-7](https://x/browse/PROJ-7)", "")
+
+	matches, err := s.Search("EXAMPLE.com")
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(matches) != 1 || matches[0].Type != "url" {
+		t.Errorf("Search() = %+v, want the url entry", matches)
+	}
+
+	if err := s.Clear(); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if entries, _ := s.Entries(); len(entries) != 0 {
+		t.Errorf("Entries() after Clear() = %+v, want none", entries)
+	}
+}
+
+func TestStore_Nil(t *testing.T) {
+	var s *Store
+	if err := s.Record("in", types.ContentTypeUnknown, "out", ""); err != nil {
+		t.Errorf("Record() error = %v", err)
+	}
+	if entries, err := s.Entries(); err != nil || entries != nil {
+		t.Errorf("Entries() = %v, %v, want nil", entries, err)
+	}
+	if FromConfig(&types.Config{History: types.HistoryConfig{Disabled: true, Dir: t.TempDir()}}) != nil {
+		t.Error("FromConfig() should return nil when history is disabled")
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package history
+
+import "os"
+
+// lockFile is a no-op where flock is unavailable; the in-process mutex
+// still serialises writers within one process
+func lockFile(f *os.File) error {
+	return nil
+}
+
+func unlockFile(f *os.File) error {
+	return nil
+}
@@ -0,0 +1,162 @@
+package jira
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+// URL styles
+const (
+	// StyleCloud is Atlassian's hosted Jira, on atlassian.net or a custom
+	// domain
+	StyleCloud = "cloud"
+	// StyleServer is self-hosted Jira Server or Data Center, which may live
+	// under a context path such as https://example.com/jira
+	StyleServer = "server"
+)
+
+// keyRegex matches an issue key such as PLAT-123
+var keyRegex = regexp.MustCompile(`^([A-Z][A-Z0-9_]*)-\d+$`)
+
+// Instance is a JIRA site and the projects whose keys link to it
+type Instance struct {
+	// Domain is the base URL without a trailing slash, including any
+	// context path
+	Domain   string
+	Projects []string
+	Prefix   string
+	// Style is StyleCloud or StyleServer, detected from the domain unless
+	// configured. Only atlassian.net is detected as Cloud.
+	Style string
+	// User and Token authenticate project discovery
+	User  string
+	Token string
+}
+
+// Instances returns the main site from jira.domain and jira.projects, if a
+// domain is set, followed by each entry of jira.instances
+func Instances(cfg *types.Config) []Instance {
+	instances := make([]Instance, 0, 1+len(cfg.JIRA.Instances))
+	if cfg.JIRA.Domain != "" {
+		instance := newInstance(cfg.JIRA.Domain, cfg.JIRA.Projects, "", cfg.JIRA.Style)
+		instance.User, instance.Token = cfg.JIRA.Discovery.User, cfg.JIRA.Discovery.Token
+		instances = append(instances, instance)
+	}
+	for _, ic := range cfg.JIRA.Instances {
+		if strings.TrimSpace(ic.Domain) == "" {
+			continue
+		}
+		instance := newInstance(ic.Domain, ic.Projects, ic.Prefix, ic.Style)
+		instance.User, instance.Token = ic.User, ic.Token
+		instances = append(instances, instance)
+	}
+	return instances
+}
+
+func newInstance(domain string, projects []string, prefix, style string) Instance {
+	domain = strings.TrimRight(strings.TrimSpace(domain), "/")
+	style = strings.ToLower(strings.TrimSpace(style))
+	if style == "" {
+		style = StyleServer
+		if u, err := url.Parse(domain); err == nil && strings.HasSuffix(strings.ToLower(u.Hostname()), ".atlassian.net") {
+			style = StyleCloud
+		}
+	}
+	return Instance{Domain: domain, Projects: projects, Prefix: strings.TrimSpace(prefix), Style: style}
+}
+
+// IsKey reports whether s looks like an issue key
+func IsKey(s string) bool {
+	return keyRegex.MatchString(s)
+}
+
+// ProjectOf returns the project part of an issue key, or "" if key isn't one
+func ProjectOf(key string) string {
+	matches := keyRegex.FindStringSubmatch(key)
+	if matches == nil {
+		return ""
+	}
+	return matches[1]
+}
+
+// ForKey returns the first instance listing the project of key
+func ForKey(cfg *types.Config, key string) (Instance, bool) {
+	project := ProjectOf(key)
+	if project == "" {
+		return Instance{}, false
+	}
+	for _, instance := range Instances(cfg) {
+		if instance.HasProject(project) {
+			return instance, true
+		}
+	}
+	return Instance{}, false
+}
+
+// ForURL returns the instance serving u: the one on u's host whose context
+// path is the longest prefix of u's path
+func ForURL(cfg *types.Config, u *url.URL) (Instance, bool) {
+	var found Instance
+	best := -1
+	for _, instance := range Instances(cfg) {
+		base, err := url.Parse(instance.Domain)
+		if err != nil || !strings.EqualFold(base.Host, u.Host) {
+			continue
+		}
+		prefix := strings.TrimRight(base.Path, "/")
+		if prefix != "" && u.Path != prefix && !strings.HasPrefix(u.Path, prefix+"/") {
+			continue
+		}
+		if len(prefix) > best {
+			found, best = instance, len(prefix)
+		}
+	}
+	return found, best >= 0
+}
+
+// ForDomain returns the instance with the given base URL
+func ForDomain(cfg *types.Config, domain string) (Instance, bool) {
+	domain = strings.TrimRight(domain, "/")
+	for _, instance := range Instances(cfg) {
+		if strings.EqualFold(instance.Domain, domain) {
+			return instance, true
+		}
+	}
+	return Instance{}, false
+}
+
+// HasProject reports whether project is one of the instance's projects
+func (i Instance) HasProject(project string) bool {
+	for _, configured := range i.Projects {
+		if configured == project {
+			return true
+		}
+	}
+	return false
+}
+
+// IssueURL returns the link to an issue
+func (i Instance) IssueURL(key string) string {
+	return i.Domain + "/browse/" + key
+}
+
+// DisplayKey returns key as shown in link text, after the instance's prefix
+func (i Instance) DisplayKey(key string) string {
+	if i.Prefix == "" {
+		return key
+	}
+	return i.Prefix + " " + key
+}
+
+// Path returns the part of u's path below the instance's context path
+func (i Instance) Path(u *url.URL) string {
+	if base, err := url.Parse(i.Domain); err == nil {
+		if prefix := strings.TrimRight(base.Path, "/"); prefix != "" {
+			return strings.TrimPrefix(u.Path, prefix)
+		}
+	}
+	return u.Path
+}
@@ -0,0 +1,88 @@
+package jira
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+func instancesConfig() *types.Config {
+	return &types.Config{JIRA: types.JIRAConfig{
+		Domain:   "https://companycam.atlassian.net/",
+		Projects: []string{"PLAT"},
+		Instances: []types.JIRAInstanceConfig{
+			{Domain: "https://jira.client.com", Projects: []string{"OPS"}},
+			{Domain: "https://jira.client.com/jira", Projects: []string{"CLI", "CLI_2"}, Prefix: "Client"},
+		},
+	}}
+}
+
+func TestInstances(t *testing.T) {
+	instances := Instances(instancesConfig())
+	if len(instances) != 3 {
+		t.Fatalf("Instances() returned %d instances, want 3", len(instances))
+	}
+	if got := instances[0]; got.Domain != "https://companycam.atlassian.net" || got.Style != StyleCloud {
+		t.Errorf("Instances()[0] = %+v, want the main cloud site without a trailing slash", got)
+	}
+	if got := instances[2]; got.Style != StyleServer || got.Prefix != "Client" {
+		t.Errorf("Instances()[2] = %+v, want a prefixed server site", got)
+	}
+}
+
+func TestForKey(t *testing.T) {
+	cfg := instancesConfig()
+	tests := []struct {
+		key      string
+		expected string
+		ok       bool
+	}{
+		{"PLAT-1", "https://companycam.atlassian.net", true},
+		{"CLI-45", "https://jira.client.com/jira", true},
+		{"CLI_2-7", "https://jira.client.com/jira", true},
+		{"NOPE-1", "", false},
+		{"plat-1", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			instance, ok := ForKey(cfg, tt.key)
+			if ok != tt.ok || instance.Domain != tt.expected {
+				t.Errorf("ForKey(%q) = %q, %v, want %q, %v", tt.key, instance.Domain, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestForURL(t *testing.T) {
+	cfg := instancesConfig()
+	tests := []struct {
+		input    string
+		expected string
+		key      string
+	}{
+		{"https://companycam.atlassian.net/browse/PLAT-1", "https://companycam.atlassian.net", "PLAT-1"},
+		{"https://jira.client.com/jira/browse/CLI-45", "https://jira.client.com/jira", "CLI-45"},
+		{"https://jira.client.com/browse/OPS-3", "https://jira.client.com", "OPS-3"},
+		{"https://jira.client.com/projects/OPS/issues/OPS-3", "https://jira.client.com", "OPS-3"},
+		{"https://jira.client.com/jirafoo/browse/OPS-3", "https://jira.client.com", ""},
+		{"https://example.com/browse/PLAT-1", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			u, _ := url.Parse(tt.input)
+			instance, ok := ForURL(cfg, u)
+			if instance.Domain != tt.expected || ok != (tt.expected != "") {
+				t.Fatalf("ForURL(%q) = %q, %v, want %q", tt.input, instance.Domain, ok, tt.expected)
+			}
+			if !ok {
+				return
+			}
+			if key := instance.ParseRoute(u).IssueKey; key != tt.key {
+				t.Errorf("ParseRoute(%q).IssueKey = %q, want %q", tt.input, key, tt.key)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package jira
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/erebusbat/markdown-tool/internal/cache"
+	"github.com/erebusbat/markdown-tool/internal/fetch"
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+// discoveryTimeout bounds a project list request. Requests are only made by
+// Refresh and Discover, never while parsing.
+const discoveryTimeout = 5 * time.Second
+
+// builtinExclude holds prefixes of common identifiers that look like keys,
+// e.g. UTF-8, SHA-256 and ISO-8601. They are never linked unless listed as
+// a project.
+var builtinExclude = []string{"UTF", "SHA", "ISO", "RFC", "AES", "RSA", "GPT", "COVID"}
+
+// Resolver finds the instance for a key, also accepting projects discovered
+// from each site's REST API and, in any-project mode, unlisted projects
+type Resolver struct {
+	config *types.Config
+	cache  *cache.Cache
+	client *fetch.Client
+}
+
+func NewResolver(cfg *types.Config, c *cache.Cache, client *fetch.Client) *Resolver {
+	return &Resolver{config: cfg, cache: c, client: client}
+}
+
+// ResolverFromConfig returns a Resolver using the configured cache and
+// network policy
+func ResolverFromConfig(cfg *types.Config) *Resolver {
+	return NewResolver(cfg, cache.FromConfig(cfg), fetch.FromConfig(cfg, fetch.Options{Timeout: discoveryTimeout}))
+}
+
+// ForKey returns the instance key links to. Excluded keys never link;
+// listed projects go to their instance, then discovered projects to the
+// site that has them, then with any_project set anything else to the main
+// site. Discovered projects are only read from the cache, so lookups never
+// wait on the network; until Refresh has filled it, they don't link.
+func (r *Resolver) ForKey(ctx context.Context, key string) (Instance, bool) {
+	return r.lookup(key, r.config.JIRA.AnyProject)
+}
+
+// ForKnownKey is ForKey without any-project mode, for keys picked out of
+// longer text where a stray match is more likely
+func (r *Resolver) ForKnownKey(ctx context.Context, key string) (Instance, bool) {
+	return r.lookup(key, false)
+}
+
+func (r *Resolver) lookup(key string, anyProject bool) (Instance, bool) {
+	project := ProjectOf(key)
+	if project == "" || excluded(r.config.JIRA.Exclude, key, project) {
+		return Instance{}, false
+	}
+	if instance, ok := ForKey(r.config, key); ok {
+		return instance, true
+	}
+	if len(project) < 2 || excluded(builtinExclude, key, project) {
+		return Instance{}, false
+	}
+
+	instances := Instances(r.config)
+	if r.config.JIRA.Discovery.Enabled {
+		for _, instance := range instances {
+			for _, discovered := range r.cached(instance) {
+				if discovered == project {
+					return instance, true
+				}
+			}
+		}
+	}
+	if anyProject && len(instances) > 0 {
+		return instances[0], true
+	}
+	return Instance{}, false
+}
+
+// excluded reports whether key or its project is in the list
+func excluded(list []string, key, project string) bool {
+	for _, entry := range list {
+		entry = strings.ToUpper(strings.TrimSpace(entry))
+		if entry == key || entry == project {
+			return true
+		}
+	}
+	return false
+}
+
+// jiraProject is the part of a project in the REST API response that is used
+type jiraProject struct {
+	Key string `json:"key"`
+}
+
+// Refresh fetches the project list of each instance that isn't cached, when
+// discovery is enabled. It runs outside parsing: in serve's background
+// refresh and from config discover. Without a cache there is nowhere to keep
+// the lists, so it does nothing.
+func (r *Resolver) Refresh(ctx context.Context) {
+	if !r.config.JIRA.Discovery.Enabled || r.cache == nil {
+		return
+	}
+	for _, instance := range Instances(r.config) {
+		r.Discover(ctx, instance)
+	}
+}
+
+// Stale reports whether discovery is enabled and an instance the network
+// policy allows has no cached project list, failed lookups included, so
+// that Refresh would make a request
+func (r *Resolver) Stale() bool {
+	if !r.config.JIRA.Discovery.Enabled || r.cache == nil {
+		return false
+	}
+	policy := fetch.NewPolicy(r.config.Network)
+	for _, instance := range Instances(r.config) {
+		u, err := url.Parse(instance.Domain)
+		if err != nil || policy.Check(u.Hostname()) != nil {
+			continue
+		}
+		if _, ok := r.cache.Get(cache.SourceJIRA, projectsURL(instance)); !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Discover returns the project keys of instance from its REST API, cached.
+// A failed lookup returns nil.
+func (r *Resolver) Discover(ctx context.Context, instance Instance) []string {
+	apiURL := projectsURL(instance)
+	value, err := r.cache.Fetch(ctx, cache.SourceJIRA, apiURL, func(ctx context.Context) (string, error) {
+		projects, err := r.fetchProjects(ctx, apiURL, instance)
+		if err != nil {
+			return "", err
+		}
+		return strings.Join(projects, ","), nil
+	})
+	if err != nil || value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// cached returns the project keys of instance stored by an earlier
+// Discover, without making a request
+func (r *Resolver) cached(instance Instance) []string {
+	entry, ok := r.cache.Get(cache.SourceJIRA, projectsURL(instance))
+	if !ok || entry.Negative || entry.Value == "" {
+		return nil
+	}
+	return strings.Split(entry.Value, ",")
+}
+
+// projectsURL is the project list of the REST API the instance's style
+// serves: version 3 on Cloud, where version 2 is deprecated, and version 2
+// on Server, which has no other
+func projectsURL(instance Instance) string {
+	if instance.Style == StyleCloud {
+		return instance.Domain + "/rest/api/3/project"
+	}
+	return instance.Domain + "/rest/api/2/project"
+}
+
+func (r *Resolver) fetchProjects(ctx context.Context, apiURL string, instance Instance) ([]string, error) {
+	headers := map[string]string{"Accept": "application/json"}
+	switch {
+	case instance.Token != "" && instance.User != "":
+		// Atlassian Cloud API tokens use basic auth with the account email
+		credentials := base64.StdEncoding.EncodeToString([]byte(instance.User + ":" + instance.Token))
+		headers["Authorization"] = "Basic " + credentials
+	case instance.Token != "":
+		headers["Authorization"] = "Bearer " + instance.Token
+	}
+
+	resp, err := r.client.GetWithHeaders(ctx, apiURL, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []jiraProject
+	if err := json.Unmarshal(resp.Body, &projects); err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(projects))
+	for _, project := range projects {
+		if project.Key != "" {
+			keys = append(keys, project.Key)
+		}
+	}
+	return keys, nil
+}
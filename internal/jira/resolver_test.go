@@ -0,0 +1,167 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/erebusbat/markdown-tool/internal/cache"
+	"github.com/erebusbat/markdown-tool/internal/fetch"
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+// newJIRAAPI serves a project list in the shape of the Jira REST API
+func newJIRAAPI(t *testing.T, requests *int32, auth *string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		*auth = r.Header.Get("Authorization")
+		if r.URL.Path != "/rest/api/2/project" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id":"10000","key":"OPS","name":"Operations"},{"id":"10001","key":"X2","name":"Experiments"}]`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestResolver_ForKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		anyProject bool
+		key        string
+		ok         bool
+	}{
+		{"Listed project", false, "PLAT-1", true},
+		{"Unlisted project", false, "MISC-1", false},
+		{"Any project", true, "MISC-1", true},
+		{"Digits in the project", true, "AB2-7", true},
+		{"Excluded key", true, "UTF-8", false},
+		{"Excluded project", true, "SKIP-12", false},
+		{"Built-in exclusion", true, "SHA-256", false},
+		{"Listed project beats built-in exclusion", false, "ISO-9", true},
+		{"Single-letter project", true, "X-1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &types.Config{JIRA: types.JIRAConfig{
+				Domain:     "https://example.atlassian.net",
+				Projects:   []string{"PLAT", "ISO"},
+				AnyProject: tt.anyProject,
+				Exclude:    []string{"utf-8", "SKIP"},
+			}}
+			instance, ok := NewResolver(cfg, nil, fetch.New(fetch.Options{})).ForKey(context.Background(), tt.key)
+			if ok != tt.ok {
+				t.Fatalf("ForKey(%q) ok = %v, want %v", tt.key, ok, tt.ok)
+			}
+			if ok && instance.Domain != "https://example.atlassian.net" {
+				t.Errorf("ForKey(%q) = %q, want the main site", tt.key, instance.Domain)
+			}
+		})
+	}
+}
+
+func TestResolver_Discover(t *testing.T) {
+	var requests int32
+	var auth string
+	server := newJIRAAPI(t, &requests, &auth)
+
+	cfg := &types.Config{
+		JIRA: types.JIRAConfig{
+			Domain:   "https://example.atlassian.net",
+			Projects: []string{"PLAT"},
+			Instances: []types.JIRAInstanceConfig{
+				{Domain: server.URL, Token: "secret"},
+			},
+			Discovery: types.JIRADiscoveryConfig{Enabled: true},
+		},
+		// The main site isn't reachable in tests; only allow the stand-in
+		Network: types.NetworkConfig{Mode: "allowlist", Allow: []string{"127.0.0.1"}},
+	}
+	client := fetch.New(fetch.Options{Policy: fetch.NewPolicy(cfg.Network)})
+	r := NewResolver(cfg, cache.New(t.TempDir(), cache.Options{}), client)
+
+	if _, ok := r.ForKey(context.Background(), "X2-5"); ok {
+		t.Error("ForKey(X2-5) linked a discovered project before Refresh")
+	}
+	if n := atomic.LoadInt32(&requests); n != 0 {
+		t.Errorf("requests = %d, want none from ForKey", n)
+	}
+	if !r.Stale() {
+		t.Error("Stale() = false before any project list is cached")
+	}
+	r.Refresh(context.Background())
+	if r.Stale() {
+		t.Error("Stale() = true after Refresh")
+	}
+
+	instance, ok := r.ForKey(context.Background(), "X2-5")
+	if !ok || instance.Domain != server.URL {
+		t.Fatalf("ForKey(X2-5) = %q, %v, want the discovered site", instance.Domain, ok)
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("Authorization = %q, want the instance token", auth)
+	}
+	if _, ok := r.ForKey(context.Background(), "NOPE-1"); ok {
+		t.Error("ForKey(NOPE-1) found an instance for an undiscovered project")
+	}
+
+	before := atomic.LoadInt32(&requests)
+	if _, ok := r.ForKey(context.Background(), "OPS-1"); !ok {
+		t.Error("ForKey(OPS-1) found no instance")
+	}
+	if after := atomic.LoadInt32(&requests); after != before {
+		t.Errorf("project list fetched again (%d requests), want it cached", after)
+	}
+}
+
+func TestResolver_Discover_Denied(t *testing.T) {
+	var requests int32
+	var auth string
+	server := newJIRAAPI(t, &requests, &auth)
+
+	cfg := &types.Config{JIRA: types.JIRAConfig{
+		Domain:    server.URL,
+		Discovery: types.JIRADiscoveryConfig{Enabled: true, User: "me@example.com", Token: "secret"},
+	}}
+	client := fetch.New(fetch.Options{Policy: fetch.NewPolicy(types.NetworkConfig{Mode: "off"}), Timeout: time.Second})
+	r := NewResolver(cfg, cache.New(t.TempDir(), cache.Options{}), client)
+
+	if r.Stale() {
+		t.Error("Stale() = true for a site the network policy blocks")
+	}
+	r.Refresh(context.Background())
+	if _, ok := r.ForKey(context.Background(), "OPS-1"); ok {
+		t.Error("ForKey(OPS-1) found an instance with the network off")
+	}
+	if requests != 0 {
+		t.Errorf("requests = %d, want none with the network off", requests)
+	}
+}
+
+func TestProjectsURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		instance Instance
+		expected string
+	}{
+		{"Cloud", newInstance("https://acme.atlassian.net", nil, "", ""), "https://acme.atlassian.net/rest/api/3/project"},
+		{"Cloud on a custom domain", newInstance("https://jira.acme.com", nil, "", StyleCloud), "https://jira.acme.com/rest/api/3/project"},
+		{"Server", newInstance("https://jira.client.com/jira", nil, "", ""), "https://jira.client.com/jira/rest/api/2/project"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := projectsURL(tt.instance); got != tt.expected {
+				t.Errorf("projectsURL() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package jira
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Route kinds, stored as the "type" metadata of a parsed JIRA URL
+const (
+	KindIssue   = "issue"
+	KindBoard   = "board"
+	KindBacklog = "backlog"
+	KindSprint  = "sprint"
+	KindFilter  = "filter"
+	KindSearch  = "jql"
+	KindQueue   = "queue"
+)
+
+var (
+	projectRegex = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+	numberRegex  = regexp.MustCompile(`^\d+$`)
+	// commentFragmentRegex matches the comment anchor of Server issue
+	// pages, e.g. #comment-20266
+	commentFragmentRegex = regexp.MustCompile(`^comment-(\d+)$`)
+)
+
+// Route is what a JIRA URL points at
+type Route struct {
+	Kind     string
+	Project  string
+	IssueKey string
+	// CommentID is the focused comment of an issue
+	CommentID string
+	BoardID   string
+	SprintID  string
+	FilterID  string
+	JQL       string
+	QueueID   string
+}
+
+// ParseRoute classifies a URL on the instance. Cloud sites serve project
+// pages under /jira/<product>/, e.g. /jira/software/c/projects/PLAT/boards/12,
+// and Server sites at the root, e.g. /projects/PLAT/issues/PLAT-123. An
+// issue selected on a board or backlog (?selectedIssue=PLAT-192) makes the
+// URL a link to that issue. The kind is empty for pages not recognised.
+func (i Instance) ParseRoute(u *url.URL) Route {
+	route := Route{}
+	query := u.Query()
+	segments := splitPath(i.Path(u))
+	arg := func(n int) string {
+		if n < len(segments) {
+			return segments[n]
+		}
+		return ""
+	}
+
+	switch arg(0) {
+	case "browse":
+		if IsKey(arg(1)) {
+			route.Kind = KindIssue
+			route.IssueKey = arg(1)
+		}
+	case "issues":
+		route.parseSearch(query)
+	case "secure":
+		switch arg(1) {
+		case "RapidBoard.jspa":
+			route.parseRapidBoard(query)
+		case "IssueNavigator.jspa":
+			route.parseSearch(query)
+		}
+	case "servicedesk":
+		// Customer portal requests, e.g. /servicedesk/customer/portal/2/SD-123
+		if last := segments[len(segments)-1]; IsKey(last) {
+			route.Kind = KindIssue
+			route.IssueKey = last
+		}
+	default:
+		projectSegments := segments
+		if i.Style == StyleCloud {
+			if arg(0) != "jira" || len(segments) < 3 {
+				break
+			}
+			projectSegments = segments[2:]
+			if projectSegments[0] == "c" {
+				projectSegments = projectSegments[1:]
+			}
+		}
+		route.parseProject(projectSegments, query)
+	}
+
+	if key := query.Get("selectedIssue"); IsKey(key) {
+		route.Kind = KindIssue
+		route.IssueKey = key
+	}
+	if route.Kind == KindIssue {
+		route.CommentID = commentID(u)
+	}
+	return route
+}
+
+// parseProject reads project pages: projects/PLAT/boards/12,
+// projects/PLAT/issues/PLAT-123, projects/SD/queues/custom/5 and so on
+func (r *Route) parseProject(segments []string, query url.Values) {
+	if len(segments) < 2 || segments[0] != "projects" || !projectRegex.MatchString(segments[1]) {
+		return
+	}
+	r.Project = segments[1]
+	rest := segments[2:]
+	arg := func(n int) string {
+		if n < len(rest) {
+			return rest[n]
+		}
+		return ""
+	}
+
+	switch arg(0) {
+	case "issues":
+		if IsKey(arg(1)) {
+			r.Kind = KindIssue
+			r.IssueKey = arg(1)
+			return
+		}
+		r.parseSearch(query)
+	case "boards":
+		if !numberRegex.MatchString(arg(1)) {
+			return
+		}
+		r.Kind = KindBoard
+		r.BoardID = arg(1)
+		switch arg(2) {
+		case "backlog":
+			r.Kind = KindBacklog
+		case "reports":
+			if sprint := query.Get("sprint"); numberRegex.MatchString(sprint) {
+				r.Kind = KindSprint
+				r.SprintID = sprint
+			}
+		}
+	case "queues":
+		// Queues are queues/custom/5, optionally followed by an open request
+		for _, segment := range rest[1:] {
+			switch {
+			case IsKey(segment):
+				r.Kind = KindIssue
+				r.IssueKey = segment
+				return
+			case numberRegex.MatchString(segment):
+				r.Kind = KindQueue
+				r.QueueID = segment
+			}
+		}
+	}
+}
+
+// parseRapidBoard reads the Server board URL,
+// /secure/RapidBoard.jspa?rapidView=12&projectKey=PLAT&view=planning
+func (r *Route) parseRapidBoard(query url.Values) {
+	board := query.Get("rapidView")
+	if !numberRegex.MatchString(board) {
+		return
+	}
+	r.Kind = KindBoard
+	r.BoardID = board
+	if project := query.Get("projectKey"); projectRegex.MatchString(project) {
+		r.Project = project
+	}
+	switch {
+	case strings.HasPrefix(query.Get("view"), "planning"):
+		r.Kind = KindBacklog
+	case query.Get("view") == "reporting" && numberRegex.MatchString(query.Get("sprint")):
+		r.Kind = KindSprint
+		r.SprintID = query.Get("sprint")
+	}
+}
+
+// parseSearch reads an issue search by JQL or by saved filter. A filter
+// changed in the search view carries both, and the JQL is what's shown.
+func (r *Route) parseSearch(query url.Values) {
+	if jql := strings.TrimSpace(query.Get("jql")); jql != "" {
+		r.Kind = KindSearch
+		r.JQL = jql
+		return
+	}
+	if filter := query.Get("filter"); numberRegex.MatchString(filter) {
+		r.Kind = KindFilter
+		r.FilterID = filter
+	}
+}
+
+// commentID returns the comment an issue URL focuses on, from
+// ?focusedCommentId= (Server and older Cloud links), ?focusedId= (Cloud)
+// or #comment-<id>
+func commentID(u *url.URL) string {
+	query := u.Query()
+	for _, param := range []string{"focusedCommentId", "focusedId"} {
+		if id := query.Get(param); numberRegex.MatchString(id) {
+			return id
+		}
+	}
+	if matches := commentFragmentRegex.FindStringSubmatch(u.Fragment); matches != nil {
+		return matches[1]
+	}
+	return ""
+}
+
+// Metadata returns the route's fields as parse metadata, omitting empty ones
+func (r Route) Metadata() map[string]string {
+	fields := map[string]string{
+		"type":       r.Kind,
+		"project":    r.Project,
+		"issue_key":  r.IssueKey,
+		"comment_id": r.CommentID,
+		"board_id":   r.BoardID,
+		"sprint_id":  r.SprintID,
+		"filter_id":  r.FilterID,
+		"jql":        r.JQL,
+		"queue_id":   r.QueueID,
+	}
+	for key, value := range fields {
+		if value == "" {
+			delete(fields, key)
+		}
+	}
+	return fields
+}
+
+// splitPath splits a URL path into its non-empty segments
+func splitPath(path string) []string {
+	segments := make([]string, 0)
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
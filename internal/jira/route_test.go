@@ -0,0 +1,59 @@
+package jira
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestInstance_ParseRoute(t *testing.T) {
+	cloud := newInstance("https://acme.atlassian.net", nil, "", "")
+	server := newInstance("https://jira.client.com/jira", nil, "", "")
+	customCloud := newInstance("https://jira.acme.com", nil, "", StyleCloud)
+
+	tests := []struct {
+		name     string
+		instance Instance
+		input    string
+		expected Route
+	}{
+		{"Issue", cloud, "https://acme.atlassian.net/browse/PLAT-192", Route{Kind: KindIssue, IssueKey: "PLAT-192"}},
+		{"Cloud comment", cloud, "https://acme.atlassian.net/browse/PLAT-192?focusedId=20266", Route{Kind: KindIssue, IssueKey: "PLAT-192", CommentID: "20266"}},
+		{"Older comment link", cloud, "https://acme.atlassian.net/browse/PLAT-192?focusedCommentId=20266", Route{Kind: KindIssue, IssueKey: "PLAT-192", CommentID: "20266"}},
+		{"Server comment anchor", server, "https://jira.client.com/jira/browse/CLI-4?focusedCommentId=7&page=com.atlassian.jira.plugin.system.issuetabpanels%3Acomment-tabpanel#comment-7", Route{Kind: KindIssue, IssueKey: "CLI-4", CommentID: "7"}},
+		{"Fragment comment", server, "https://jira.client.com/jira/browse/CLI-4#comment-7", Route{Kind: KindIssue, IssueKey: "CLI-4", CommentID: "7"}},
+		{"Board", cloud, "https://acme.atlassian.net/jira/software/c/projects/PLAT/boards/12", Route{Kind: KindBoard, Project: "PLAT", BoardID: "12"}},
+		{"Selected issue on board", cloud, "https://acme.atlassian.net/jira/software/projects/PLAT/boards/12?selectedIssue=PLAT-192", Route{Kind: KindIssue, Project: "PLAT", BoardID: "12", IssueKey: "PLAT-192"}},
+		{"Backlog", cloud, "https://acme.atlassian.net/jira/software/c/projects/PLAT/boards/12/backlog", Route{Kind: KindBacklog, Project: "PLAT", BoardID: "12"}},
+		{"Sprint report", cloud, "https://acme.atlassian.net/jira/software/c/projects/PLAT/boards/12/reports/sprint-retrospective?sprint=34", Route{Kind: KindSprint, Project: "PLAT", BoardID: "12", SprintID: "34"}},
+		{"Other report", cloud, "https://acme.atlassian.net/jira/software/c/projects/PLAT/boards/12/reports/burndown-chart", Route{Kind: KindBoard, Project: "PLAT", BoardID: "12"}},
+		{"Filter", cloud, "https://acme.atlassian.net/issues/?filter=10432", Route{Kind: KindFilter, FilterID: "10432"}},
+		{"Built-in filter", cloud, "https://acme.atlassian.net/issues/?filter=allopenissues", Route{}},
+		{"JQL", cloud, "https://acme.atlassian.net/issues/?jql=project%20%3D%20PLAT", Route{Kind: KindSearch, JQL: "project = PLAT"}},
+		{"Edited filter shows JQL", cloud, "https://acme.atlassian.net/issues/?filter=10432&jql=project%20%3D%20PLAT", Route{Kind: KindSearch, JQL: "project = PLAT"}},
+		{"Project issue search", cloud, "https://acme.atlassian.net/jira/software/c/projects/PLAT/issues?jql=project%20%3D%20PLAT", Route{Kind: KindSearch, Project: "PLAT", JQL: "project = PLAT"}},
+		{"Queue", cloud, "https://acme.atlassian.net/jira/servicedesk/projects/SD/queues/custom/5", Route{Kind: KindQueue, Project: "SD", QueueID: "5"}},
+		{"Request in a queue", cloud, "https://acme.atlassian.net/jira/servicedesk/projects/SD/queues/custom/5/SD-88", Route{Kind: KindIssue, Project: "SD", QueueID: "5", IssueKey: "SD-88"}},
+		{"Customer portal request", cloud, "https://acme.atlassian.net/servicedesk/customer/portal/2/SD-88", Route{Kind: KindIssue, IssueKey: "SD-88"}},
+		{"Server project issue", server, "https://jira.client.com/jira/projects/CLI/issues/CLI-4", Route{Kind: KindIssue, Project: "CLI", IssueKey: "CLI-4"}},
+		{"Server board", server, "https://jira.client.com/jira/secure/RapidBoard.jspa?rapidView=7&projectKey=CLI", Route{Kind: KindBoard, Project: "CLI", BoardID: "7"}},
+		{"Server backlog", server, "https://jira.client.com/jira/secure/RapidBoard.jspa?rapidView=7&view=planning.nodetail", Route{Kind: KindBacklog, BoardID: "7"}},
+		{"Server sprint report", server, "https://jira.client.com/jira/secure/RapidBoard.jspa?rapidView=7&view=reporting&chart=sprintRetrospective&sprint=3", Route{Kind: KindSprint, BoardID: "7", SprintID: "3"}},
+		{"Server issue navigator", server, "https://jira.client.com/jira/secure/IssueNavigator.jspa?jql=assignee%3DcurrentUser()", Route{Kind: KindSearch, JQL: "assignee=currentUser()"}},
+		{"Cloud project path on Server", server, "https://jira.client.com/jira/jira/software/c/projects/CLI/boards/7", Route{}},
+		{"Cloud board on a custom domain", customCloud, "https://jira.acme.com/jira/software/c/projects/PLAT/boards/12", Route{Kind: KindBoard, Project: "PLAT", BoardID: "12"}},
+		{"Unknown page", cloud, "https://acme.atlassian.net/jira/your-work", Route{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("url.Parse(%q) error = %v", tt.input, err)
+			}
+			if got := tt.instance.ParseRoute(u); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseRoute(%q) = %+v, want %+v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
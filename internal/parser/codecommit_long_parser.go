@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"context"
 	"regexp"
 	"strings"
 
@@ -61,7 +62,7 @@ func (p *CodeCommitLongParser) CanHandle(input string) bool {
 	return hasCodeCommitIndicators && hasRepoIndicator && hasPRIndicator && hasTitleWithNumber
 }
 
-func (p *CodeCommitLongParser) Parse(input string) (*types.ParseContext, error) {
+func (p *CodeCommitLongParser) Parse(_ context.Context, input string) (*types.ParseContext, error) {
 	if !p.CanHandle(input) {
 		return nil, nil
 	}
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"context"
 	"net/url"
 	"regexp"
 	"strings"
@@ -29,7 +30,7 @@ func (p *CodeCommitParser) CanHandle(input string) bool {
 		strings.Contains(u.Path, "/pull-requests/")
 }
 
-func (p *CodeCommitParser) Parse(input string) (*types.ParseContext, error) {
+func (p *CodeCommitParser) Parse(_ context.Context, input string) (*types.ParseContext, error) {
 	if !p.CanHandle(input) {
 		return nil, nil
 	}
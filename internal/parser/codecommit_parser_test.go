@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"context"
 	"testing"
 
 	"github.com/erebusbat/markdown-tool/pkg/types"
@@ -95,7 +96,7 @@ Pull requests
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ctx, err := parser.Parse(tt.input)
+			ctx, err := parser.Parse(context.Background(), tt.input)
 			if err != nil {
 				t.Fatalf("Parse() error = %v", err)
 			}
@@ -217,7 +218,7 @@ func TestCodeCommitParser_Parse(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ctx, err := parser.Parse(tt.input)
+			ctx, err := parser.Parse(context.Background(), tt.input)
 			if err != nil {
 				t.Fatalf("Parse() error = %v", err)
 			}
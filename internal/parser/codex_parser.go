@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"context"
 	"regexp"
 	"strings"
 
@@ -21,7 +22,7 @@ func (p *CodexParser) CanHandle(input string) bool {
 	return codexThreadRe.MatchString(strings.TrimSpace(input))
 }
 
-func (p *CodexParser) Parse(input string) (*types.ParseContext, error) {
+func (p *CodexParser) Parse(_ context.Context, input string) (*types.ParseContext, error) {
 	trimmed := strings.TrimSpace(input)
 	matches := codexThreadRe.FindStringSubmatch(trimmed)
 	if matches == nil {
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"context"
 	"testing"
 
 	"github.com/erebusbat/markdown-tool/pkg/types"
@@ -99,7 +100,7 @@ func TestCodexParser_Parse(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ctx, err := p.Parse(tt.input)
+			ctx, err := p.Parse(context.Background(), tt.input)
 			if err != nil {
 				t.Fatalf("Parse(%q) returned error: %v", tt.input, err)
 			}
@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/erebusbat/markdown-tool/internal/github"
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+var (
+	// commitSHARegex matches a full or abbreviated commit SHA as printed by
+	// git log and git log --oneline
+	commitSHARegex = regexp.MustCompile(`^(?:[0-9a-fA-F]{40}|[0-9a-fA-F]{7,12})$`)
+	hexLetterRegex = regexp.MustCompile(`[a-fA-F]`)
+	digitRegex     = regexp.MustCompile(`[0-9]`)
+)
+
+// CommitSHAParser links a lone commit SHA to the commit in the default repo,
+// or in the repository of the current directory's GitHub remote
+type CommitSHAParser struct {
+	config *types.Config
+	hosts  *github.HostSet
+	// dir is where the git remote is looked for
+	dir string
+}
+
+func NewCommitSHAParser(cfg *types.Config) *CommitSHAParser {
+	dir := ""
+	if !cfg.NoGitRemote {
+		dir, _ = os.Getwd()
+	}
+	return &CommitSHAParser{config: cfg, hosts: github.NewHostSet(cfg), dir: dir}
+}
+
+func (p *CommitSHAParser) CanHandle(input string) bool {
+	input = strings.TrimSpace(input)
+	if !commitSHARegex.MatchString(input) {
+		return false
+	}
+	if len(input) == 40 {
+		return true
+	}
+	// A short SHA needs both a letter and a digit, so phone numbers and
+	// words such as "defaced" are left alone
+	return hexLetterRegex.MatchString(input) && digitRegex.MatchString(input)
+}
+
+func (p *CommitSHAParser) Parse(_ context.Context, input string) (*types.ParseContext, error) {
+	if !p.CanHandle(input) {
+		return nil, nil
+	}
+
+	host, org, repo, ok := p.hosts.Current(p.dir)
+	if !ok {
+		return nil, nil
+	}
+
+	sha := strings.ToLower(strings.TrimSpace(input))
+	return &types.ParseContext{
+		OriginalInput: input,
+		DetectedType:  types.ContentTypeGitHubURL,
+		Confidence:    80,
+		Metadata: map[string]interface{}{
+			"host":   host.Name,
+			"org":    org,
+			"repo":   repo,
+			"type":   github.KindCommit,
+			"number": sha,
+			"url":    host.URL(org, repo, github.KindCommit, sha),
+		},
+	}, nil
+}
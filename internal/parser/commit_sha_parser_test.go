@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+func TestCommitSHAParser_Parse(t *testing.T) {
+	cfg := &types.Config{
+		GitHub: types.GitHubConfig{DefaultOrg: "CompanyCam", DefaultRepo: "Company-Cam-API"},
+	}
+	parser := NewCommitSHAParser(cfg)
+
+	tests := []struct {
+		name        string
+		input       string
+		expectedURL string
+	}{
+		{"Full SHA", "aa062a602a02d33f4a6e7880809ac3609fe1417b", "https://github.com/CompanyCam/Company-Cam-API/commit/aa062a602a02d33f4a6e7880809ac3609fe1417b"},
+		{"Short SHA", " AA062A6\n", "https://github.com/CompanyCam/Company-Cam-API/commit/aa062a6"},
+		{"Twelve characters", "aa062a602a02", "https://github.com/CompanyCam/Company-Cam-API/commit/aa062a602a02"},
+		{"Phone number", "5551234", ""},
+		{"Hex word", "defaced", ""},
+		{"Too short", "aa062a", ""},
+		{"Between lengths", "aa062a602a02d3", ""},
+		{"Not hex", "aa062g6", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := parser.Parse(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if tt.expectedURL == "" {
+				if ctx != nil {
+					t.Errorf("Parse() = %+v, want nil", ctx.Metadata)
+				}
+				return
+			}
+			if ctx == nil {
+				t.Fatal("Parse() returned nil context")
+			}
+			if ctx.DetectedType != types.ContentTypeGitHubURL || ctx.Metadata["type"] != "commit" {
+				t.Errorf("Parse() = %v %v, want a GitHub commit", ctx.DetectedType, ctx.Metadata["type"])
+			}
+			if got := ctx.Metadata["url"]; got != tt.expectedURL {
+				t.Errorf("url = %v, want %v", got, tt.expectedURL)
+			}
+		})
+	}
+
+	// Without a default repo or a git remote there is nothing to link to
+	parser = NewCommitSHAParser(&types.Config{})
+	parser.dir = t.TempDir()
+	if ctx, _ := parser.Parse(context.Background(), "aa062a6"); ctx != nil {
+		t.Errorf("Parse() without a repository = %+v, want nil", ctx.Metadata)
+	}
+
+	// verify turns the git remote fallback off
+	if parser := NewCommitSHAParser(&types.Config{NoGitRemote: true}); parser.dir != "" {
+		t.Errorf("dir = %q with NoGitRemote, want none", parser.dir)
+	}
+}
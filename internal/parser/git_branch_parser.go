@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/erebusbat/markdown-tool/internal/github"
+	"github.com/erebusbat/markdown-tool/internal/jira"
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+var (
+	// branchNameRegex matches a plausible branch name: no spaces, no URL
+	branchNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/+-]*[A-Za-z0-9]$`)
+	// branchJIRAKeyRegex finds a JIRA key between separators, in any case,
+	// e.g. feature/PLAT-123-sso-logging or plat-123_sso
+	branchJIRAKeyRegex = regexp.MustCompile(`(?:^|[/_-])([A-Za-z][A-Za-z0-9]*-\d+)(?:$|[/_.-])`)
+)
+
+// GitBranchParser recognises a branch name containing a JIRA key of a
+// configured project, such as feature/PLAT-123-sso-logging, and links both
+// the branch in the default or current repository and the issue
+type GitBranchParser struct {
+	config   *types.Config
+	hosts    *github.HostSet
+	resolver *jira.Resolver
+	// dir is where the git remote is looked for
+	dir string
+}
+
+func NewGitBranchParser(cfg *types.Config) *GitBranchParser {
+	dir := ""
+	if !cfg.NoGitRemote {
+		dir, _ = os.Getwd()
+	}
+	return &GitBranchParser{config: cfg, hosts: github.NewHostSet(cfg), resolver: jira.ResolverFromConfig(cfg), dir: dir}
+}
+
+func (p *GitBranchParser) CanHandle(input string) bool {
+	input = strings.TrimSpace(input)
+	return branchNameRegex.MatchString(input) && !strings.Contains(input, "..") && !strings.Contains(input, "//")
+}
+
+func (p *GitBranchParser) Parse(ctx context.Context, input string) (*types.ParseContext, error) {
+	if !p.CanHandle(input) {
+		return nil, nil
+	}
+	branch := strings.TrimSpace(input)
+
+	issueKey, instance := p.issueKey(ctx, branch)
+	if issueKey == "" || strings.EqualFold(issueKey, branch) {
+		// A bare key is left to the JIRA key parser
+		return nil, nil
+	}
+
+	metadata := map[string]interface{}{
+		"branch":    branch,
+		"issue_key": issueKey,
+		"domain":    instance.Domain,
+	}
+	if host, org, repo, ok := p.hosts.Current(p.dir); ok {
+		segments := []string{org, repo, github.KindTree}
+		for _, segment := range strings.Split(branch, "/") {
+			segments = append(segments, url.PathEscape(segment))
+		}
+		metadata["host"] = host.Name
+		metadata["org"] = org
+		metadata["repo"] = repo
+		metadata["url"] = host.URL(segments...)
+	}
+
+	return &types.ParseContext{
+		OriginalInput: input,
+		DetectedType:  types.ContentTypeGitBranch,
+		Confidence:    80,
+		Metadata:      metadata,
+	}, nil
+}
+
+// issueKey returns the first key in branch, uppercased, whose project is
+// configured, and the instance it belongs to. Any-project mode only applies
+// to keys written in upper case, so e.g. release/v1-2 isn't taken for one.
+func (p *GitBranchParser) issueKey(ctx context.Context, branch string) (string, jira.Instance) {
+	for _, matches := range branchJIRAKeyRegex.FindAllStringSubmatch(branch, -1) {
+		key := strings.ToUpper(matches[1])
+		lookup := p.resolver.ForKnownKey
+		if key == matches[1] {
+			lookup = p.resolver.ForKey
+		}
+		if instance, ok := lookup(ctx, key); ok {
+			return key, instance
+		}
+	}
+	return "", jira.Instance{}
+}
@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+func TestGitBranchParser_Parse(t *testing.T) {
+	cfg := &types.Config{
+		GitHub: types.GitHubConfig{DefaultOrg: "CompanyCam", DefaultRepo: "Company-Cam-API"},
+		JIRA: types.JIRAConfig{
+			Domain:   "https://companycam.atlassian.net",
+			Projects: []string{"PLAT", "SPEED"},
+		},
+	}
+	parser := NewGitBranchParser(cfg)
+
+	tests := []struct {
+		name        string
+		input       string
+		expectedKey string
+		expectedURL string
+	}{
+		{"Feature branch", "feature/PLAT-123-sso-logging", "PLAT-123", "https://github.com/CompanyCam/Company-Cam-API/tree/feature/PLAT-123-sso-logging"},
+		{"Lowercase key", "plat-9_fix\n", "PLAT-9", "https://github.com/CompanyCam/Company-Cam-API/tree/plat-9_fix"},
+		{"Key at the end", "bugfix/SPEED-42", "SPEED-42", "https://github.com/CompanyCam/Company-Cam-API/tree/bugfix/SPEED-42"},
+		{"Bare key", "PLAT-123", "", ""},
+		{"Unconfigured project", "feature/OTHER-1-thing", "", ""},
+		{"No key", "feature/sso-logging", "", ""},
+		{"Key inside a word", "feature/XPLAT-123", "", ""},
+		{"Sentence", "see feature/PLAT-123-sso", "", ""},
+		{"URL", "https://example.com/PLAT-123-x", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := parser.Parse(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if tt.expectedKey == "" {
+				if ctx != nil {
+					t.Errorf("Parse() = %+v, want nil", ctx.Metadata)
+				}
+				return
+			}
+			if ctx == nil {
+				t.Fatal("Parse() returned nil context")
+			}
+			if ctx.DetectedType != types.ContentTypeGitBranch {
+				t.Errorf("DetectedType = %v, want %v", ctx.DetectedType, types.ContentTypeGitBranch)
+			}
+			if got := ctx.Metadata["issue_key"]; got != tt.expectedKey {
+				t.Errorf("issue_key = %v, want %v", got, tt.expectedKey)
+			}
+			if got := ctx.Metadata["url"]; got != tt.expectedURL {
+				t.Errorf("url = %v, want %v", got, tt.expectedURL)
+			}
+		})
+	}
+}
+
+func TestGitBranchParser_Parse_AnyProject(t *testing.T) {
+	cfg := &types.Config{
+		JIRA: types.JIRAConfig{
+			Domain:     "https://companycam.atlassian.net",
+			AnyProject: true,
+		},
+	}
+	parser := NewGitBranchParser(cfg)
+
+	tests := []struct {
+		input       string
+		expectedKey string
+	}{
+		{"feature/OPS-5-rotate-keys", "OPS-5"},
+		{"feature/ops-5-rotate-keys", ""},
+		{"release/v1-2", ""},
+		{"fix/UTF-8-decoding", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			ctx, err := parser.Parse(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			var got interface{} = ""
+			if ctx != nil {
+				got = ctx.Metadata["issue_key"]
+			}
+			if got != tt.expectedKey {
+				t.Errorf("issue_key = %v, want %q", got, tt.expectedKey)
+			}
+		})
+	}
+}
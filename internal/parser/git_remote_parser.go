@@ -0,0 +1,123 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/erebusbat/markdown-tool/internal/github"
+	"github.com/erebusbat/markdown-tool/internal/gitutil"
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+var (
+	// forgeHosts serve a repository's web page at the same host and path as
+	// its remote. GitHub hosts come from the github config instead.
+	forgeHosts = map[string]bool{
+		"gitlab.com":    true,
+		"bitbucket.org": true,
+		"codeberg.org":  true,
+	}
+
+	// codeCommitRemoteRegex matches the host of an AWS CodeCommit remote,
+	// capturing the region
+	codeCommitRemoteRegex = regexp.MustCompile(`^git-codecommit(?:-fips)?\.([a-z0-9-]+)\.amazonaws\.com$`)
+)
+
+// GitRemoteParser turns git remote URLs and clone strings, such as
+// git@github.com:CompanyCam/Company-Cam-API.git, ssh://git@github.com/... or
+// https://gitlab.com/group/project.git, into the repository's web page.
+// Remotes on GitHub hosts become GitHub repository links so mappings apply.
+type GitRemoteParser struct {
+	config *types.Config
+	hosts  *github.HostSet
+}
+
+func NewGitRemoteParser(cfg *types.Config) *GitRemoteParser {
+	return &GitRemoteParser{config: cfg, hosts: github.NewHostSet(cfg)}
+}
+
+func (p *GitRemoteParser) CanHandle(input string) bool {
+	input = strings.TrimSpace(input)
+	if input == "" || strings.ContainsAny(input, " \t\n") {
+		return false
+	}
+
+	if !strings.Contains(input, "://") {
+		// scp-style, e.g. git@github.com:org/repo.git
+		_, ok := gitutil.ParseRemoteURL(input)
+		return ok
+	}
+
+	u, err := url.Parse(input)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "ssh", "git", "git+ssh":
+		return true
+	case "http", "https":
+		// A web page URL is left to the URL parser
+		return strings.HasSuffix(u.Path, ".git") || codeCommitRemoteRegex.MatchString(strings.ToLower(u.Hostname()))
+	}
+	return false
+}
+
+func (p *GitRemoteParser) Parse(_ context.Context, input string) (*types.ParseContext, error) {
+	if !p.CanHandle(input) {
+		return nil, nil
+	}
+
+	remote, ok := gitutil.ParseRemoteURL(input)
+	if !ok {
+		return nil, nil
+	}
+	metadata := map[string]interface{}{"remote": remote.URL}
+
+	if host, ok := p.hosts.Lookup(remote.Host); ok {
+		// GitHub has no nested groups
+		if strings.Contains(remote.Org, "/") {
+			return nil, nil
+		}
+		metadata["host"] = host.Name
+		metadata["org"] = remote.Org
+		metadata["repo"] = remote.Repo
+		metadata["url"] = host.URL(remote.Org, remote.Repo)
+		return &types.ParseContext{
+			OriginalInput: input,
+			DetectedType:  types.ContentTypeGitHubURL,
+			Confidence:    95,
+			Metadata:      metadata,
+		}, nil
+	}
+
+	switch {
+	case forgeHosts[remote.Host]:
+		metadata["host"] = remote.Host
+		metadata["org"] = remote.Org
+		metadata["repo"] = remote.Repo
+		metadata["name"] = remote.Org + "/" + remote.Repo
+		metadata["url"] = fmt.Sprintf("https://%s/%s/%s", remote.Host, remote.Org, remote.Repo)
+	case codeCommitRemoteRegex.MatchString(remote.Host):
+		region := codeCommitRemoteRegex.FindStringSubmatch(remote.Host)[1]
+		if remote.Org != "v1/repos" {
+			return nil, nil
+		}
+		metadata["host"] = remote.Host
+		metadata["region"] = region
+		metadata["repo"] = remote.Repo
+		metadata["name"] = remote.Repo
+		metadata["url"] = fmt.Sprintf("https://%s.console.aws.amazon.com/codesuite/codecommit/repositories/%s/browse?region=%s", region, remote.Repo, region)
+	default:
+		return nil, nil
+	}
+
+	return &types.ParseContext{
+		OriginalInput: input,
+		DetectedType:  types.ContentTypeGitRemote,
+		Confidence:    95,
+		Metadata:      metadata,
+	}, nil
+}
@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+func TestGitRemoteParser_Parse(t *testing.T) {
+	cfg := &types.Config{
+		GitHub: types.GitHubConfig{
+			Hosts: []types.GitHubHostConfig{{Host: "github.acme.com"}},
+		},
+	}
+	parser := NewGitRemoteParser(cfg)
+
+	tests := []struct {
+		name         string
+		input        string
+		expectedType types.ContentType
+		expectedURL  string
+	}{
+		{"scp-style GitHub", "git@github.com:CompanyCam/Company-Cam-API.git", types.ContentTypeGitHubURL, "https://github.com/CompanyCam/Company-Cam-API"},
+		{"SSH GitHub", "ssh://git@github.com/CompanyCam/Company-Cam-API.git", types.ContentTypeGitHubURL, "https://github.com/CompanyCam/Company-Cam-API"},
+		{"HTTPS clone URL", " https://github.com/golang/go.git\n", types.ContentTypeGitHubURL, "https://github.com/golang/go"},
+		{"GitHub Enterprise", "git@github.acme.com:platform/api.git", types.ContentTypeGitHubURL, "https://github.acme.com/platform/api"},
+		{"GitLab subgroup", "https://gitlab.com/group/sub/project.git", types.ContentTypeGitRemote, "https://gitlab.com/group/sub/project"},
+		{"Bitbucket", "git@bitbucket.org:team/repo.git", types.ContentTypeGitRemote, "https://bitbucket.org/team/repo"},
+		{"CodeCommit", "https://git-codecommit.us-east-1.amazonaws.com/v1/repos/MyRepo", types.ContentTypeGitRemote, "https://us-east-1.console.aws.amazon.com/codesuite/codecommit/repositories/MyRepo/browse?region=us-east-1"},
+		{"GitHub web page", "https://github.com/golang/go", types.ContentTypeUnknown, ""},
+		{"Unknown host", "git@example.com:team/repo.git", types.ContentTypeUnknown, ""},
+		{"Nested path on GitHub", "git@github.com:a/b/c.git", types.ContentTypeUnknown, ""},
+		{"Sentence", "clone git@github.com:golang/go.git please", types.ContentTypeUnknown, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := parser.Parse(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if tt.expectedURL == "" {
+				if ctx != nil {
+					t.Errorf("Parse() = %+v, want nil", ctx.Metadata)
+				}
+				return
+			}
+			if ctx == nil {
+				t.Fatal("Parse() returned nil context")
+			}
+			if ctx.DetectedType != tt.expectedType {
+				t.Errorf("DetectedType = %v, want %v", ctx.DetectedType, tt.expectedType)
+			}
+			if got := ctx.Metadata["url"]; got != tt.expectedURL {
+				t.Errorf("url = %v, want %v", got, tt.expectedURL)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package parser
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/erebusbat/markdown-tool/internal/github"
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+var (
+	// listMetaRegex matches the line under a list entry's title, e.g.
+	// "#6549 opened 3 days ago by alice", "#6549 · alice opened 3 days ago"
+	// or "#15217 by alice was merged 2 days ago"
+	listMetaRegex = regexp.MustCompile(`^#(\d+)\b\s*(?:·\s*)?(.*\b(?:opened|closed|merged|by)\b.*)$`)
+	// listStatusRegex matches a status line without a number, e.g.
+	// "opened 3 days ago by alice"
+	listStatusRegex = regexp.MustCompile(`^(?:opened|(?:was )?closed|(?:was )?merged)\b.*\b(?:ago|on)\b`)
+	// listRepoRegex matches the org/repo shown above entries in search
+	// results and notifications, optionally with the number
+	listRepoRegex = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9-]*)/([A-Za-z0-9._-]+)(?:\s+#(\d+))?$`)
+	// listLabelRegex matches lines that look like labels rather than titles:
+	// short and lowercase, e.g. "bug" or "good first issue"
+	listLabelRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9 :/._-]{0,29}$`)
+)
+
+// minListEntries is the number of entries that makes a paste a list; a
+// single entry is left to GitHubLongParser
+const minListEntries = 2
+
+// GitHubListParser handles pastes of several issues or pull requests, such
+// as an issues list, search results or the notifications page, producing
+// one entry per item
+type GitHubListParser struct {
+	config *types.Config
+	hosts  *github.HostSet
+}
+
+func NewGitHubListParser(cfg *types.Config) *GitHubListParser {
+	return &GitHubListParser{config: cfg, hosts: github.NewHostSet(cfg)}
+}
+
+// githubListEntry is one issue or pull request found in a list paste
+type githubListEntry struct {
+	org, repo string
+	number    string
+	title     string
+	state     string
+	labels    []string
+	// fromTitle marks entries read from a "Title #123" line, whose status
+	// line may follow
+	fromTitle bool
+}
+
+func (p *GitHubListParser) CanHandle(input string) bool {
+	lines := nonEmptyLines(input)
+	if len(lines) < minListEntries {
+		return false
+	}
+
+	numbered := 0
+	for _, line := range lines {
+		if listMetaRegex.MatchString(line) || hasIssueTitleWithNumber(line) {
+			numbered++
+		}
+	}
+	return numbered >= minListEntries
+}
+
+func (p *GitHubListParser) Parse(_ context.Context, input string) (*types.ParseContext, error) {
+	if !p.CanHandle(input) {
+		return nil, nil
+	}
+	lines := nonEmptyLines(input)
+
+	entries := readGitHubListEntries(lines)
+	if len(entries) < minListEntries {
+		return nil, nil
+	}
+
+	// Entries without their own org/repo use the one the page is for
+	entryLines := make(map[string]bool)
+	for _, entry := range entries {
+		entryLines[entry.title] = true
+		for _, label := range entry.labels {
+			entryLines[label] = true
+		}
+	}
+	org, repo := findOrgRepo(lines, func(line string) bool {
+		return entryLines[line] || listMetaRegex.MatchString(line) || listRepoRegex.MatchString(line) || hasIssueTitleWithNumber(line)
+	})
+	if org == "" || repo == "" {
+		host := p.hosts.Default()
+		org, repo = host.DefaultOrg, host.DefaultRepo
+	}
+
+	issueType := detectGitHubIssueType(lines)
+	if strings.Contains(input, "is:pr") {
+		issueType = "pull"
+	}
+
+	items := make([]map[string]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.org == "" {
+			entry.org, entry.repo = org, repo
+		}
+		if entry.org == "" || entry.repo == "" {
+			continue
+		}
+
+		kind := issueType
+		if entry.state == "merged" {
+			kind = "pull"
+		}
+		items = append(items, map[string]string{
+			"host":   p.hosts.ForRepo(entry.org, entry.repo).Name,
+			"org":    entry.org,
+			"repo":   entry.repo,
+			"number": entry.number,
+			"title":  stripLeadingJiraKey(entry.title),
+			"type":   kind,
+			"state":  entry.state,
+			"labels": strings.Join(entry.labels, ", "),
+		})
+	}
+	if len(items) < minListEntries {
+		return nil, nil
+	}
+
+	return &types.ParseContext{
+		OriginalInput: input,
+		DetectedType:  types.ContentTypeGitHubList,
+		Confidence:    95,
+		Metadata: map[string]interface{}{
+			"items": items,
+		},
+	}, nil
+}
+
+// readGitHubListEntries finds the entries in the lines of a list paste.
+// Titles come either with their number ("Title #123") or on the lines
+// above a status line ("#123 opened 3 days ago by alice"), in which case
+// label lines may sit between the title and the status line.
+func readGitHubListEntries(lines []string) []*githubListEntry {
+	entries := make([]*githubListEntry, 0)
+	var last *githubListEntry
+	// blockStart is the first line after the previous entry
+	blockStart := 0
+	var qualifiedOrg, qualifiedRepo string
+
+	add := func(entry *githubListEntry) {
+		entry.org, entry.repo = qualifiedOrg, qualifiedRepo
+		qualifiedOrg, qualifiedRepo = "", ""
+		entries = append(entries, entry)
+		last = entry
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if matches := listRepoRegex.FindStringSubmatch(line); matches != nil {
+			qualifiedOrg, qualifiedRepo = matches[1], matches[2]
+			blockStart = i + 1
+			if matches[3] == "" {
+				continue
+			}
+			// Notifications show "org/repo #123" above the title
+			if i+1 < len(lines) && !listMetaRegex.MatchString(lines[i+1]) && !hasIssueTitleWithNumber(lines[i+1]) {
+				add(&githubListEntry{number: matches[3], title: lines[i+1]})
+				i++
+				blockStart = i + 1
+			}
+			continue
+		}
+
+		if matches := listMetaRegex.FindStringSubmatch(line); matches != nil {
+			state := listEntryState(matches[2])
+			if last != nil && last.fromTitle && last.number == matches[1] {
+				last.state = state
+			} else if title, labels := splitTitleAndLabels(lines[blockStart:i], last != nil); title != "" {
+				add(&githubListEntry{number: matches[1], title: title, labels: labels, state: state})
+			}
+			blockStart = i + 1
+			continue
+		}
+
+		if listStatusRegex.MatchString(line) {
+			if last != nil && last.state == "" {
+				last.state = listEntryState(line)
+			}
+			blockStart = i + 1
+			continue
+		}
+
+		if hasIssueTitleWithNumber(line) && !strings.HasPrefix(line, "#") {
+			title, number := extractIssueTitleAndNumber(line)
+			add(&githubListEntry{number: number, title: title, fromTitle: true})
+			blockStart = i + 1
+		}
+	}
+
+	return entries
+}
+
+// splitTitleAndLabels picks the title and labels of an entry from the lines
+// above its status line. Scanning upwards, label-like lines are labels and
+// the first other line is the title. Before the first entry the block also
+// holds page navigation, so a title is required; after one, the first line
+// of the block is the title even if it looks like a label.
+func splitTitleAndLabels(block []string, afterEntry bool) (string, []string) {
+	for i := len(block) - 1; i >= 0; i-- {
+		if !listLabelRegex.MatchString(block[i]) {
+			return block[i], append([]string(nil), block[i+1:]...)
+		}
+	}
+	if afterEntry && len(block) > 0 {
+		return block[0], append([]string(nil), block[1:]...)
+	}
+	return "", nil
+}
+
+// listEntryState reads open, closed, merged or draft from a status line
+func listEntryState(status string) string {
+	lower := strings.ToLower(status)
+	switch {
+	case strings.Contains(lower, "draft"):
+		return "draft"
+	case strings.Contains(lower, "merged"):
+		return "merged"
+	case strings.Contains(lower, "closed"):
+		return "closed"
+	case strings.Contains(lower, "opened"):
+		return "open"
+	}
+	return ""
+}
+
+func nonEmptyLines(input string) []string {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(input, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
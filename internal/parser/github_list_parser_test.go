@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+func TestGitHubListParser_Parse(t *testing.T) {
+	cfg := &types.Config{
+		GitHub: types.GitHubConfig{DefaultOrg: "CompanyCam", DefaultRepo: "Company-Cam-API"},
+	}
+	parser := NewGitHubListParser(cfg)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected []map[string]string
+	}{
+		{
+			name: "Issues list",
+			input: `CompanyCam
+companycam-mobile
+Code
+Issues
+78 Open
+Fix SSO logging
+bug
+good first issue
+#6549 opened 3 days ago by alice
+PLAT-12: Add caching
+#6548 by bob was closed 2 days ago`,
+			expected: []map[string]string{
+				{"org": "CompanyCam", "repo": "companycam-mobile", "number": "6549", "title": "Fix SSO logging", "state": "open", "labels": "bug, good first issue"},
+				{"org": "CompanyCam", "repo": "companycam-mobile", "number": "6548", "title": "Add caching", "state": "closed", "labels": ""},
+			},
+		},
+		{
+			name:  "Title lines use the default repo",
+			input: "Fix SSO logging #6549\nopened 3 days ago by alice\nAdd caching #6548",
+			expected: []map[string]string{
+				{"org": "CompanyCam", "repo": "Company-Cam-API", "number": "6549", "title": "Fix SSO logging", "state": "open"},
+				{"org": "CompanyCam", "repo": "Company-Cam-API", "number": "6548", "title": "Add caching", "state": ""},
+			},
+		},
+		{
+			name: "Search results name their repository",
+			input: `is:pr author:alice
+CompanyCam/Company-Cam-API
+Fix SSO logging
+#15217 by alice was merged 2 days ago
+golang/go
+Add generics
+#43651 opened 5 days ago by alice`,
+			expected: []map[string]string{
+				{"org": "CompanyCam", "repo": "Company-Cam-API", "number": "15217", "type": "pull", "state": "merged"},
+				{"org": "golang", "repo": "go", "number": "43651", "type": "pull", "state": "open"},
+			},
+		},
+		{
+			name:  "Notifications",
+			input: "CompanyCam/Company-Cam-API #15217\nFix SSO logging\nCompanyCam/companycam-mobile #12\nCrash on start",
+			expected: []map[string]string{
+				{"repo": "Company-Cam-API", "number": "15217", "title": "Fix SSO logging"},
+				{"repo": "companycam-mobile", "number": "12", "title": "Crash on start"},
+			},
+		},
+		{
+			name:  "Single entry is left to the long parser",
+			input: "CompanyCam\ncompanycam-mobile\nFix SSO logging #6549",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := parser.Parse(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if tt.expected == nil {
+				if ctx != nil {
+					t.Errorf("Parse() = %+v, want nil", ctx.Metadata)
+				}
+				return
+			}
+			if ctx == nil {
+				t.Fatal("Parse() returned nil context")
+			}
+			if ctx.DetectedType != types.ContentTypeGitHubList {
+				t.Errorf("DetectedType = %v, want %v", ctx.DetectedType, types.ContentTypeGitHubList)
+			}
+
+			items, _ := ctx.Metadata["items"].([]map[string]string)
+			if len(items) != len(tt.expected) {
+				t.Fatalf("got %d items, want %d: %v", len(items), len(tt.expected), items)
+			}
+			for i, want := range tt.expected {
+				got := make(map[string]string, len(want))
+				for key := range want {
+					got[key] = items[i][key]
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("item %d = %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
@@ -1,18 +1,21 @@
 package parser
 
 import (
+	"context"
 	"regexp"
 	"strings"
 
+	"github.com/erebusbat/markdown-tool/internal/github"
 	"github.com/erebusbat/markdown-tool/pkg/types"
 )
 
 type GitHubLongParser struct {
 	config *types.Config
+	hosts  *github.HostSet
 }
 
 func NewGitHubLongParser(cfg *types.Config) *GitHubLongParser {
-	return &GitHubLongParser{config: cfg}
+	return &GitHubLongParser{config: cfg, hosts: github.NewHostSet(cfg)}
 }
 
 func (p *GitHubLongParser) CanHandle(input string) bool {
@@ -61,7 +64,7 @@ func (p *GitHubLongParser) CanHandle(input string) bool {
 	return hasOrgCandidate && hasRepoCandidate && (hasIssueTitle || hasIssueNumberLine)
 }
 
-func (p *GitHubLongParser) Parse(input string) (*types.ParseContext, error) {
+func (p *GitHubLongParser) Parse(_ context.Context, input string) (*types.ParseContext, error) {
 	if !p.CanHandle(input) {
 		return nil, nil
 	}
@@ -108,47 +111,34 @@ func (p *GitHubLongParser) Parse(input string) (*types.ParseContext, error) {
 	issueTitle = stripLeadingJiraKey(issueTitle)
 
 	// Second pass: find org and repo names
-	// In GitHub UI copies, the first line is typically the org, second line is the repo
-	var foundOrg bool
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" || line == rawTitleLine || hasStandaloneIssueNumberLine(line) {
-			continue
-		}
-		if issueTitle != "" && strings.Contains(line, issueTitle) {
-			continue
-		}
-
-		// First valid GitHub name should be the org
-		if !foundOrg && isValidGitHubName(line) {
-			org = line
-			foundOrg = true
-			continue
-		}
-
-		// Second valid name (that's different from org) should be the repo
-		if foundOrg && isValidRepoName(line) && line != org {
-			repo = line
-			break
-		}
-	}
+	org, repo = findOrgRepo(lines, func(line string) bool {
+		return line == rawTitleLine || hasStandaloneIssueNumberLine(line) || strings.Contains(line, issueTitle)
+	})
 
 	if org == "" || repo == "" {
 		return nil, nil
 	}
 
+	metadata := map[string]interface{}{
+		"host":   p.hosts.ForRepo(org, repo).Name,
+		"org":    org,
+		"repo":   repo,
+		"title":  issueTitle,
+		"number": issueNumber,
+		"type":   issueType,
+	}
+	for key, value := range extractPullRequestDetails(lines, rawTitleLine, org) {
+		metadata[key] = value
+	}
+	if _, ok := metadata["base"]; ok {
+		metadata["type"] = "pull"
+	}
+
 	ctx := &types.ParseContext{
 		OriginalInput: input,
 		DetectedType:  types.ContentTypeGitHubLong,
 		Confidence:    90,
-		Metadata: map[string]interface{}{
-			"org":    org,
-			"repo":   repo,
-			"title":  issueTitle,
-			"number": issueNumber,
-			"type":   issueType,
-		},
+		Metadata:      metadata,
 	}
 
 	return ctx, nil
@@ -190,9 +180,10 @@ func (p *GitHubLongParser) parseSimpleIssueTitle(input string) (*types.ParseCont
 
 	issueTitle = stripLeadingJiraKey(issueTitle)
 
-	// Use default org and repo from config
-	org := p.config.GitHub.DefaultOrg
-	repo := p.config.GitHub.DefaultRepo
+	// Use the default org and repo from config
+	host := p.hosts.Default()
+	org := host.DefaultOrg
+	repo := host.DefaultRepo
 
 	if org == "" || repo == "" {
 		return nil, nil
@@ -203,6 +194,7 @@ func (p *GitHubLongParser) parseSimpleIssueTitle(input string) (*types.ParseCont
 		DetectedType:  types.ContentTypeGitHubLong,
 		Confidence:    95, // Higher confidence for simple patterns
 		Metadata: map[string]interface{}{
+			"host":   host.Name,
 			"org":    org,
 			"repo":   repo,
 			"title":  issueTitle,
@@ -214,6 +206,34 @@ func (p *GitHubLongParser) parseSimpleIssueTitle(input string) (*types.ParseCont
 	return ctx, nil
 }
 
+// findOrgRepo finds the org and repo names in GitHub UI content, skipping
+// lines that belong to issues. In GitHub UI copies, the first line is
+// typically the org and the second line the repo.
+func findOrgRepo(lines []string, skip func(line string) bool) (org, repo string) {
+	var foundOrg bool
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || skip(line) {
+			continue
+		}
+
+		// First valid GitHub name should be the org
+		if !foundOrg && isValidGitHubName(line) {
+			org = line
+			foundOrg = true
+			continue
+		}
+
+		// Second valid name (that's different from org) should be the repo
+		if foundOrg && isValidRepoName(line) && line != org {
+			return org, line
+		}
+	}
+
+	return org, ""
+}
+
 // extractUsernameAndIssue extracts the issue title and number from a line with username prefix
 func extractUsernameAndIssue(s string) (title, number string) {
 	// Pattern: username followed by title and #number
@@ -413,6 +433,9 @@ func detectGitHubIssueType(lines []string) string {
 		if strings.Contains(lower, "pull request") && !strings.Contains(lower, "pull requests") {
 			return "pull"
 		}
+		if pullRequestMergeRegex.MatchString(strings.TrimSpace(line)) {
+			return "pull"
+		}
 	}
 
 	if hasRepositoryNavigation && hasPullRequests && hasAgents {
@@ -422,6 +445,65 @@ func detectGitHubIssueType(lines []string) string {
 	return "issues"
 }
 
+var (
+	// pullRequestMergeRegex matches the line under a pull request title, e.g.
+	// "alice wants to merge 3 commits into main from feature-x" or
+	// "bob merged 3 commits into main from feature-x"
+	pullRequestMergeRegex = regexp.MustCompile(`^(\S+)\s+(wants to merge|merged)\s+(\d+)\s+commits?\s+into\s+(\S+)\s+from\s+(\S+)`)
+	// issueOpenedRegex matches the line under an issue title, e.g.
+	// "alice opened this issue on Jan 2 · 3 comments"
+	issueOpenedRegex = regexp.MustCompile(`^(\S+)\s+opened this (?:issue|pull request)\b`)
+)
+
+// githubStateBadges maps the state badge shown under a title to a state
+var githubStateBadges = map[string]string{
+	"Open":   "open",
+	"Closed": "closed",
+	"Merged": "merged",
+	"Draft":  "draft",
+}
+
+// extractPullRequestDetails reads the state badge, author and branches that
+// follow the title line in a copied issue or pull request page. It returns
+// the keys found among state, author, merged_by, base, head and commits.
+func extractPullRequestDetails(lines []string, titleLine, org string) map[string]string {
+	details := make(map[string]string)
+
+	afterTitle := false
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if !afterTitle {
+			afterTitle = line != "" && line == titleLine
+			continue
+		}
+
+		if state, ok := githubStateBadges[line]; ok && details["state"] == "" {
+			details["state"] = state
+			continue
+		}
+
+		if matches := pullRequestMergeRegex.FindStringSubmatch(line); matches != nil && details["base"] == "" {
+			if matches[2] == "merged" {
+				details["merged_by"] = matches[1]
+				details["state"] = "merged"
+			} else {
+				details["author"] = matches[1]
+			}
+			details["commits"] = matches[3]
+			// A branch in the same repository may be shown as org:branch
+			details["base"] = strings.TrimPrefix(matches[4], org+":")
+			details["head"] = strings.TrimPrefix(matches[5], org+":")
+			continue
+		}
+
+		if matches := issueOpenedRegex.FindStringSubmatch(line); matches != nil && details["author"] == "" {
+			details["author"] = matches[1]
+		}
+	}
+
+	return details
+}
+
 var leadingJiraKeyRegex = regexp.MustCompile(`^\s*(\[[A-Z][A-Z0-9]+-\d+\]\s*|[A-Z][A-Z0-9]+-\d+:\s*)`)
 
 func stripLeadingJiraKey(title string) string {
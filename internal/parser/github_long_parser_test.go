@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -432,7 +433,7 @@ Lettercarrier6 My long Descr#285`,
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ctx, err := parser.Parse(tt.input)
+			ctx, err := parser.Parse(context.Background(), tt.input)
 
 			if err != nil {
 				t.Fatalf("Parse() error = %v", err)
@@ -708,3 +709,84 @@ func TestExtractUsernameAndIssue(t *testing.T) {
 		})
 	}
 }
+
+func TestGitHubLongParser_Parse_EnterpriseDefaults(t *testing.T) {
+	cfg := &types.Config{
+		GitHub: types.GitHubConfig{
+			Hosts: []types.GitHubHostConfig{{
+				Host:        "github.acme.com",
+				DefaultOrg:  "platform",
+				DefaultRepo: "api",
+			}},
+		},
+	}
+	parser := NewGitHubLongParser(cfg)
+
+	ctx, err := parser.Parse(context.Background(), "Fix login redirect #42")
+	if err != nil || ctx == nil {
+		t.Fatalf("Parse() = %v, %v", ctx, err)
+	}
+	if ctx.Metadata["host"] != "github.acme.com" || ctx.Metadata["org"] != "platform" || ctx.Metadata["repo"] != "api" {
+		t.Errorf("Metadata = %v, want github.acme.com platform/api", ctx.Metadata)
+	}
+}
+
+func TestGitHubLongParser_Parse_PullRequestDetails(t *testing.T) {
+	parser := NewGitHubLongParser(&types.Config{})
+
+	header := "CompanyCam\nCompany-Cam-API\nCode\nIssues\nPull requests\nFix SSO logging #15217\n"
+	tests := []struct {
+		name     string
+		input    string
+		expected map[string]string
+	}{
+		{
+			name:  "Open pull request",
+			input: header + "Open\nalice wants to merge 3 commits into main from feature-x\nConversation",
+			expected: map[string]string{
+				"type": "pull", "state": "open", "author": "alice", "base": "main", "head": "feature-x", "commits": "3",
+			},
+		},
+		{
+			name:  "Merged pull request",
+			input: header + "Merged\nbob merged 1 commit into CompanyCam:main from CompanyCam:feature-x",
+			expected: map[string]string{
+				"type": "pull", "state": "merged", "merged_by": "bob", "base": "main", "head": "feature-x", "commits": "1",
+			},
+		},
+		{
+			name:  "Draft from a fork",
+			input: header + "Draft\ncarol wants to merge 2 commits into main from carol:sso",
+			expected: map[string]string{
+				"type": "pull", "state": "draft", "author": "carol", "base": "main", "head": "carol:sso",
+			},
+		},
+		{
+			name:  "Closed issue",
+			input: header + "Closed\ndave opened this issue on Jan 2 · 3 comments",
+			expected: map[string]string{
+				"state": "closed", "author": "dave",
+			},
+		},
+		{
+			name:     "Badge before the title is ignored",
+			input:    "CompanyCam\nCompany-Cam-API\nOpen\nFix SSO logging #15217",
+			expected: map[string]string{"state": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := parser.Parse(context.Background(), tt.input)
+			if err != nil || ctx == nil {
+				t.Fatalf("Parse() = %v, %v", ctx, err)
+			}
+			for key, want := range tt.expected {
+				got, _ := ctx.Metadata[key].(string)
+				if got != want {
+					t.Errorf("%s = %q, want %q", key, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/erebusbat/markdown-tool/internal/github"
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+var (
+	// githubRefRegex matches org/repo#123, repo#123 and #123
+	githubRefRegex = regexp.MustCompile(`^(?:([A-Za-z0-9][A-Za-z0-9-]*/)?([A-Za-z0-9._-]+))?#(\d+)$`)
+	// githubCommitRefRegex matches org/repo@sha and repo@sha
+	githubCommitRefRegex = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9-]*/)?([A-Za-z0-9._-]+)@([0-9a-fA-F]{7,40})$`)
+)
+
+// GitHubShorthandParser expands GitHub references as written in PR
+// descriptions and chat, such as CompanyCam/API#15217, API#15217, #15217
+// and CompanyCam/API@aa062a6, into links. Mapped display names are resolved
+// back to the real repository; a bare number uses the default org and repo.
+type GitHubShorthandParser struct {
+	config *types.Config
+	hosts  *github.HostSet
+}
+
+func NewGitHubShorthandParser(cfg *types.Config) *GitHubShorthandParser {
+	return &GitHubShorthandParser{config: cfg, hosts: github.NewHostSet(cfg)}
+}
+
+func (p *GitHubShorthandParser) CanHandle(input string) bool {
+	input = strings.TrimSpace(input)
+	return githubRefRegex.MatchString(input) || githubCommitRefRegex.MatchString(input)
+}
+
+func (p *GitHubShorthandParser) Parse(_ context.Context, input string) (*types.ParseContext, error) {
+	trimmed := strings.TrimSpace(input)
+
+	var name, kind, number string
+	if matches := githubRefRegex.FindStringSubmatch(trimmed); matches != nil {
+		name = matches[1] + matches[2]
+		kind = github.KindIssues
+		number = matches[3]
+	} else if matches := githubCommitRefRegex.FindStringSubmatch(trimmed); matches != nil {
+		name = matches[1] + matches[2]
+		kind = github.KindCommit
+		number = strings.ToLower(matches[3])
+	} else {
+		return nil, nil
+	}
+
+	var host github.Host
+	var org, repo string
+	if name == "" {
+		host = p.hosts.Default()
+		org, repo = host.DefaultOrg, host.DefaultRepo
+	} else {
+		var ok bool
+		if host, org, repo, ok = p.hosts.Resolve(name); !ok {
+			return nil, nil
+		}
+	}
+	if org == "" || repo == "" {
+		return nil, nil
+	}
+
+	// GitHub redirects /issues/N to the pull request when N is one
+	return &types.ParseContext{
+		OriginalInput: input,
+		DetectedType:  types.ContentTypeGitHubURL,
+		Confidence:    85,
+		Metadata: map[string]interface{}{
+			"host":   host.Name,
+			"org":    org,
+			"repo":   repo,
+			"type":   kind,
+			"number": number,
+			"url":    host.URL(org, repo, kind, number),
+		},
+	}, nil
+}
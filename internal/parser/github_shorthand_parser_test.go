@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+func TestGitHubShorthandParser_Parse(t *testing.T) {
+	cfg := &types.Config{
+		GitHub: types.GitHubConfig{
+			DefaultOrg:  "CompanyCam",
+			DefaultRepo: "Company-Cam-API",
+			Mappings: map[string]string{
+				"companycam/company-cam-api":   "CompanyCam/API",
+				"companycam/companycam-mobile": "Mobile",
+			},
+			Hosts: []types.GitHubHostConfig{{
+				Host:     "github.acme.com",
+				Mappings: map[string]string{"platform/monolith": "Mono"},
+			}},
+		},
+	}
+	parser := NewGitHubShorthandParser(cfg)
+
+	tests := []struct {
+		name        string
+		input       string
+		expectedURL string
+	}{
+		{"Mapped org/repo", "CompanyCam/API#15217", "https://github.com/CompanyCam/Company-Cam-API/issues/15217"},
+		{"Mapped repository part", "API#15217", "https://github.com/CompanyCam/Company-Cam-API/issues/15217"},
+		{"Mapped bare name", "Mobile#12", "https://github.com/CompanyCam/companycam-mobile/issues/12"},
+		{"Mapped name on another host", "Mono#7", "https://github.acme.com/platform/monolith/issues/7"},
+		{"Literal org/repo", "golang/go#1", "https://github.com/golang/go/issues/1"},
+		{"Default repository by name", "company-cam-api#3", "https://github.com/CompanyCam/Company-Cam-API/issues/3"},
+		{"Unknown repository", "web#3", ""},
+		{"Language name in prose", "C#7", ""},
+		{"Another language name", "F#3", ""},
+		{"Bare number", " #15217\n", "https://github.com/CompanyCam/Company-Cam-API/issues/15217"},
+		{"Commit", "CompanyCam/API@AA062A6", "https://github.com/CompanyCam/Company-Cam-API/commit/aa062a6"},
+		{"Short SHA is not a commit", "golang/go@abc12", ""},
+		{"Sentence", "see #12", ""},
+		{"Two references", "API#1 API#2", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := parser.Parse(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if tt.expectedURL == "" {
+				if ctx != nil {
+					t.Errorf("Parse() = %+v, want nil", ctx.Metadata)
+				}
+				return
+			}
+			if ctx == nil {
+				t.Fatal("Parse() returned nil context")
+			}
+			if ctx.DetectedType != types.ContentTypeGitHubURL {
+				t.Errorf("DetectedType = %v, want %v", ctx.DetectedType, types.ContentTypeGitHubURL)
+			}
+			if got := ctx.Metadata["url"]; got != tt.expectedURL {
+				t.Errorf("Metadata[url] = %v, want %v", got, tt.expectedURL)
+			}
+		})
+	}
+}
+
+func TestGitHubShorthandParser_NoDefaults(t *testing.T) {
+	parser := NewGitHubShorthandParser(&types.Config{})
+
+	for _, input := range []string{"#12", "API#12"} {
+		if ctx, _ := parser.Parse(context.Background(), input); ctx != nil {
+			t.Errorf("Parse(%q) = %+v, want nil without a default org", input, ctx.Metadata)
+		}
+	}
+}
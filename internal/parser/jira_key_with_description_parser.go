@@ -1,9 +1,11 @@
 package parser
 
 import (
+	"context"
 	"regexp"
 	"strings"
 
+	"github.com/erebusbat/markdown-tool/internal/jira"
 	"github.com/erebusbat/markdown-tool/pkg/types"
 )
 
@@ -31,11 +33,12 @@ func isNumericCountOnly(s string) bool {
 }
 
 type JIRAKeyWithDescriptionParser struct {
-	config *types.Config
+	config   *types.Config
+	resolver *jira.Resolver
 }
 
 func NewJIRAKeyWithDescriptionParser(cfg *types.Config) *JIRAKeyWithDescriptionParser {
-	return &JIRAKeyWithDescriptionParser{config: cfg}
+	return &JIRAKeyWithDescriptionParser{config: cfg, resolver: jira.ResolverFromConfig(cfg)}
 }
 
 func (p *JIRAKeyWithDescriptionParser) CanHandle(input string) bool {
@@ -48,8 +51,7 @@ func (p *JIRAKeyWithDescriptionParser) CanHandle(input string) bool {
 
 	// First line should be a JIRA key
 	firstLine := strings.TrimSpace(lines[0])
-	jiraKeyRegex := regexp.MustCompile(`^[A-Z]+-\d+$`)
-	if !jiraKeyRegex.MatchString(firstLine) {
+	if !jira.IsKey(firstLine) {
 		return false
 	}
 
@@ -71,7 +73,7 @@ func (p *JIRAKeyWithDescriptionParser) CanHandle(input string) bool {
 	return hasDescription
 }
 
-func (p *JIRAKeyWithDescriptionParser) Parse(input string) (*types.ParseContext, error) {
+func (p *JIRAKeyWithDescriptionParser) Parse(ctx context.Context, input string) (*types.ParseContext, error) {
 	if !p.CanHandle(input) {
 		return nil, nil
 	}
@@ -88,17 +90,11 @@ func (p *JIRAKeyWithDescriptionParser) Parse(input string) (*types.ParseContext,
 	}
 	projectKey := parts[0]
 
-	// Check if this project is configured
-	configured := false
-	for _, validProject := range p.config.JIRA.Projects {
-		if validProject == projectKey {
-			configured = true
-			break
-		}
-	}
-
-	if !configured {
-		return nil, nil // Don't handle unconfigured projects
+	// Find the instance that lists this project; keys of other projects
+	// can't be linked, so leave them alone
+	instance, ok := p.resolver.ForKey(ctx, jiraKey)
+	if !ok {
+		return nil, nil
 	}
 
 	// Extract description from lines 3 onwards.
@@ -146,7 +142,7 @@ func (p *JIRAKeyWithDescriptionParser) Parse(input string) (*types.ParseContext,
 
 	description := strings.Join(descriptionLines, " ")
 
-	ctx := &types.ParseContext{
+	parsed := &types.ParseContext{
 		OriginalInput: input,
 		DetectedType:  types.ContentTypeJIRAKeyWithDescription,
 		Confidence:    98, // Higher confidence than simple JIRA key
@@ -154,8 +150,9 @@ func (p *JIRAKeyWithDescriptionParser) Parse(input string) (*types.ParseContext,
 			"issue_key":   jiraKey,
 			"project":     projectKey,
 			"description": description,
+			"domain":      instance.Domain,
 		},
 	}
 
-	return ctx, nil
+	return parsed, nil
 }
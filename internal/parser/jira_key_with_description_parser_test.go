@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"context"
 	"testing"
 
 	"github.com/erebusbat/markdown-tool/pkg/types"
@@ -111,6 +112,7 @@ description`,
 func TestJIRAKeyWithDescriptionParser_Parse(t *testing.T) {
 	cfg := &types.Config{
 		JIRA: types.JIRAConfig{
+			Domain:   "https://companycam.atlassian.net",
 			Projects: []string{"PLAT", "SPEED", "HQ"},
 		},
 	}
@@ -241,7 +243,7 @@ some description`,
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ctx, err := parser.Parse(tt.input)
+			ctx, err := parser.Parse(context.Background(), tt.input)
 
 			if err != nil {
 				t.Fatalf("Parse() error = %v", err)
@@ -1,27 +1,28 @@
 package parser
 
 import (
-	"regexp"
+	"context"
 	"strings"
 
+	"github.com/erebusbat/markdown-tool/internal/jira"
 	"github.com/erebusbat/markdown-tool/pkg/types"
 )
 
 type JIRAKeyParser struct {
-	config *types.Config
+	config   *types.Config
+	resolver *jira.Resolver
 }
 
 func NewJIRAKeyParser(cfg *types.Config) *JIRAKeyParser {
-	return &JIRAKeyParser{config: cfg}
+	return &JIRAKeyParser{config: cfg, resolver: jira.ResolverFromConfig(cfg)}
 }
 
 func (p *JIRAKeyParser) CanHandle(input string) bool {
 	// Check if input looks like a JIRA key (PROJECT-123)
-	re := regexp.MustCompile(`^[A-Z]+-\d+$`)
-	return re.MatchString(strings.TrimSpace(input))
+	return jira.IsKey(strings.TrimSpace(input))
 }
 
-func (p *JIRAKeyParser) Parse(input string) (*types.ParseContext, error) {
+func (p *JIRAKeyParser) Parse(ctx context.Context, input string) (*types.ParseContext, error) {
 	trimmed := strings.TrimSpace(input)
 	if !p.CanHandle(trimmed) {
 		return nil, nil
@@ -35,28 +36,23 @@ func (p *JIRAKeyParser) Parse(input string) (*types.ParseContext, error) {
 
 	projectKey := parts[0]
 
-	// Check if this project is configured
-	configured := false
-	for _, validProject := range p.config.JIRA.Projects {
-		if validProject == projectKey {
-			configured = true
-			break
-		}
-	}
-
-	if !configured {
-		return nil, nil // Don't handle unconfigured projects
+	// Find the instance that lists this project; keys of other projects
+	// can't be linked, so leave them alone
+	instance, ok := p.resolver.ForKey(ctx, trimmed)
+	if !ok {
+		return nil, nil
 	}
 
-	ctx := &types.ParseContext{
+	parsed := &types.ParseContext{
 		OriginalInput: input,
 		DetectedType:  types.ContentTypeJIRAKey,
 		Confidence:    95,
 		Metadata: map[string]interface{}{
 			"issue_key": trimmed,
 			"project":   projectKey,
+			"domain":    instance.Domain,
 		},
 	}
 
-	return ctx, nil
+	return parsed, nil
 }
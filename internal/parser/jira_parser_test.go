@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"context"
 	"testing"
 
 	"github.com/erebusbat/markdown-tool/pkg/types"
@@ -43,6 +44,7 @@ func TestJIRAKeyParser_CanHandle(t *testing.T) {
 func TestJIRAKeyParser_Parse(t *testing.T) {
 	cfg := &types.Config{
 		JIRA: types.JIRAConfig{
+			Domain:   "https://companycam.atlassian.net",
 			Projects: []string{"PLAT", "SPEED"},
 		},
 	}
@@ -90,7 +92,7 @@ func TestJIRAKeyParser_Parse(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ctx, err := parser.Parse(tt.input)
+			ctx, err := parser.Parse(context.Background(), tt.input)
 
 			if err != nil {
 				t.Fatalf("Parse() error = %v", err)
@@ -125,3 +127,91 @@ func TestJIRAKeyParser_Parse(t *testing.T) {
 		})
 	}
 }
+
+func TestJIRAKeyParser_Parse_NoDomain(t *testing.T) {
+	cfg := &types.Config{
+		JIRA: types.JIRAConfig{
+			Projects: []string{"PLAT"},
+		},
+	}
+	parser := NewJIRAKeyParser(cfg)
+
+	ctx, err := parser.Parse(context.Background(), "PLAT-123")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if ctx != nil {
+		t.Errorf("Expected nil context without a JIRA domain, got %+v", ctx)
+	}
+}
+
+func TestJIRAKeyParser_Parse_Instances(t *testing.T) {
+	cfg := &types.Config{
+		JIRA: types.JIRAConfig{
+			Domain:   "https://companycam.atlassian.net",
+			Projects: []string{"PLAT"},
+			Instances: []types.JIRAInstanceConfig{
+				{Domain: "https://jira.client.com/jira/", Projects: []string{"CLI"}, Prefix: "Client"},
+			},
+		},
+	}
+	parser := NewJIRAKeyParser(cfg)
+
+	tests := []struct {
+		input          string
+		expectedDomain string
+	}{
+		{"PLAT-123", "https://companycam.atlassian.net"},
+		{"CLI-45", "https://jira.client.com/jira"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			ctx, err := parser.Parse(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if ctx == nil {
+				t.Fatal("Parse() returned nil context")
+			}
+			if domain := ctx.Metadata["domain"]; domain != tt.expectedDomain {
+				t.Errorf("Metadata[domain] = %v, want %v", domain, tt.expectedDomain)
+			}
+		})
+	}
+}
+
+func TestJIRAKeyParser_Parse_AnyProject(t *testing.T) {
+	cfg := &types.Config{
+		JIRA: types.JIRAConfig{
+			Domain:     "https://companycam.atlassian.net",
+			AnyProject: true,
+			Exclude:    []string{"ABC-123"},
+		},
+	}
+	parser := NewJIRAKeyParser(cfg)
+
+	tests := []struct {
+		input         string
+		expectSuccess bool
+	}{
+		{"OPS-12", true},
+		{"AB2C-7", true},
+		{"DATA_ENG-4", true},
+		{"ABC-123", false},
+		{"UTF-8", false},
+		{"ISO-8601", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			ctx, err := parser.Parse(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if (ctx != nil) != tt.expectSuccess {
+				t.Errorf("Parse(%q) = %+v, want success %v", tt.input, ctx, tt.expectSuccess)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"context"
 	"regexp"
 	"strings"
 
@@ -19,7 +20,7 @@ func (p *OpenCodeSessionParser) CanHandle(input string) bool {
 	return p.findSessionToken(input) != ""
 }
 
-func (p *OpenCodeSessionParser) Parse(input string) (*types.ParseContext, error) {
+func (p *OpenCodeSessionParser) Parse(_ context.Context, input string) (*types.ParseContext, error) {
 	token := p.findSessionToken(input)
 	if token == "" {
 		return nil, nil
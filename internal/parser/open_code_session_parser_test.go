@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"context"
 	"testing"
 
 	"github.com/erebusbat/markdown-tool/pkg/types"
@@ -106,7 +107,7 @@ func TestOpenCodeSessionParser_Parse(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ctx, err := parser.Parse(tt.input)
+			ctx, err := parser.Parse(context.Background(), tt.input)
 			if err != nil {
 				t.Fatalf("Parse(%q) returned error: %v", tt.input, err)
 			}
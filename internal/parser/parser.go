@@ -1,9 +1,20 @@
 package parser
 
 import (
+	"context"
+	"sync"
+	"time"
+
 	"github.com/erebusbat/markdown-tool/pkg/types"
 )
 
+// DefaultTimeout is the global parse deadline used when none is configured
+const DefaultTimeout = 800 * time.Millisecond
+
+// abandonGrace is how long ParseAll keeps waiting after the deadline for
+// parsers to hand back their offline result before abandoning them
+const abandonGrace = 50 * time.Millisecond
+
 // ParseContext holds data collected during parsing
 type ParseContext struct {
 	OriginalInput string
@@ -15,15 +26,103 @@ type ParseContext struct {
 // GetParsers returns all available parsers
 func GetParsers(cfg *types.Config) []types.Parser {
 	return []types.Parser{
+		NewGitRemoteParser(cfg), // Before URL, which reads a .git clone URL as a page
 		NewURLParser(cfg),
+		NewGitHubShorthandParser(cfg), // Before long format, which reads "API#1" as a title
+		NewGitHubListParser(cfg),      // Before long format, which takes only the first entry
 		NewGitHubLongParser(cfg),
 		NewCodeCommitLongParser(cfg), // Process long format before short URL
 		NewCodeCommitParser(cfg),
 		NewJIRAKeyWithDescriptionParser(cfg), // Higher priority than simple JIRA key
 		NewJIRAKeyParser(cfg),
+		NewGitBranchParser(cfg),
+		NewCommitSHAParser(cfg), // Digit-only input is left to the phone parser
 		NewPhoneParser(cfg),
 		NewRaycastParser(cfg),
 		NewOpenCodeSessionParser(cfg),
 		NewCodexParser(cfg),
 	}
 }
+
+// Timeout returns the configured parse deadline, falling back to DefaultTimeout
+func Timeout(cfg *types.Config) time.Duration {
+	if cfg == nil || cfg.ParseTimeout <= 0 {
+		return DefaultTimeout
+	}
+	return cfg.ParseTimeout
+}
+
+// ParseAll runs every parser concurrently and returns the successful parse
+// contexts in parser priority order (the order of the parsers slice).
+//
+// All parsers share a single deadline derived from ctx and timeout. Parsers
+// are expected to honour it and return their offline result; any parser that
+// is still running shortly after the deadline is abandoned and its result
+// discarded.
+func ParseAll(ctx context.Context, parsers []types.Parser, input string, timeout time.Duration) []*types.ParseContext {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		index int
+		ctx   *types.ParseContext
+	}
+
+	// Buffered so abandoned parsers can still finish without leaking
+	results := make(chan result, len(parsers))
+	var wg sync.WaitGroup
+	for i, p := range parsers {
+		wg.Add(1)
+		go func(i int, p types.Parser) {
+			defer wg.Done()
+			parsed, err := p.Parse(ctx, input)
+			if err != nil {
+				parsed = nil
+			}
+			results <- result{index: i, ctx: parsed}
+		}(i, p)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	collected := make([]*types.ParseContext, len(parsers))
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		// Give parsers a moment to return their degraded result
+		grace := time.NewTimer(abandonGrace)
+		defer grace.Stop()
+		select {
+		case <-done:
+		case <-grace.C:
+		}
+	}
+
+	// Drain whatever has been delivered so far
+drain:
+	for {
+		select {
+		case r := <-results:
+			collected[r.index] = r.ctx
+		default:
+			break drain
+		}
+	}
+
+	contexts := make([]*types.ParseContext, 0, len(parsers))
+	for _, parsed := range collected {
+		if parsed != nil {
+			contexts = append(contexts, parsed)
+		}
+	}
+	return contexts
+}
@@ -0,0 +1,126 @@
+package parser
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+// stubParser returns a fixed result after an optional delay, ignoring ctx
+type stubParser struct {
+	delay  time.Duration
+	result *types.ParseContext
+}
+
+func (s *stubParser) CanHandle(input string) bool { return true }
+
+func (s *stubParser) Parse(_ context.Context, input string) (*types.ParseContext, error) {
+	time.Sleep(s.delay)
+	return s.result, nil
+}
+
+func TestParseAll_PreservesParserOrder(t *testing.T) {
+	first := &types.ParseContext{DetectedType: types.ContentTypeURL}
+	second := &types.ParseContext{DetectedType: types.ContentTypeJIRAKey}
+
+	parsers := []types.Parser{
+		&stubParser{delay: 20 * time.Millisecond, result: first},
+		&stubParser{result: nil},
+		&stubParser{result: second},
+	}
+
+	contexts := ParseAll(context.Background(), parsers, "input", time.Second)
+	if len(contexts) != 2 {
+		t.Fatalf("ParseAll() returned %d contexts, want 2", len(contexts))
+	}
+	if contexts[0] != first || contexts[1] != second {
+		t.Errorf("ParseAll() did not preserve parser order")
+	}
+}
+
+func TestParseAll_SlowFetcherDegradesToOfflineResult(t *testing.T) {
+	cfg := &types.Config{}
+	urlParser := NewURLParser(cfg)
+	urlParser.youtubeTitleFetcher = func(ctx context.Context, targetURL string) (string, error) {
+		// Simulate a hung network call that only gives up when cancelled
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(10 * time.Second):
+			return "Too Late", nil
+		}
+	}
+
+	start := time.Now()
+	contexts := ParseAll(context.Background(), []types.Parser{urlParser}, "https://www.youtube.com/watch?v=abc123", 50*time.Millisecond)
+	elapsed := time.Since(start)
+
+	if elapsed > time.Second {
+		t.Fatalf("ParseAll() took %v, want it bounded by the deadline", elapsed)
+	}
+	if len(contexts) != 1 {
+		t.Fatalf("ParseAll() returned %d contexts, want 1", len(contexts))
+	}
+
+	ctx := contexts[0]
+	if ctx.DetectedType != types.ContentTypeYouTubeURL {
+		t.Errorf("DetectedType = %v, want %v", ctx.DetectedType, types.ContentTypeYouTubeURL)
+	}
+	if ctx.Metadata["video_id"] != "abc123" {
+		t.Errorf("Metadata[video_id] = %v, want abc123", ctx.Metadata["video_id"])
+	}
+	if _, ok := ctx.Metadata["title"]; ok {
+		t.Errorf("Metadata[title] should be unset when the fetch times out, got %v", ctx.Metadata["title"])
+	}
+}
+
+func TestParseAll_AbandonsParsersIgnoringDeadline(t *testing.T) {
+	fast := &types.ParseContext{DetectedType: types.ContentTypeJIRAKey}
+	parsers := []types.Parser{
+		&stubParser{delay: 5 * time.Second, result: &types.ParseContext{DetectedType: types.ContentTypeURL}},
+		&stubParser{result: fast},
+	}
+
+	start := time.Now()
+	contexts := ParseAll(context.Background(), parsers, "input", 20*time.Millisecond)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("ParseAll() took %v, want it bounded by the deadline", elapsed)
+	}
+	if len(contexts) != 1 || contexts[0] != fast {
+		t.Errorf("ParseAll() = %v, want only the fast parser's result", contexts)
+	}
+}
+
+func TestParseAll_ParentCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	urlParser := NewURLParser(&types.Config{})
+	called := false
+	urlParser.youtubeTitleFetcher = func(context.Context, string) (string, error) {
+		called = true
+		return "Title", nil
+	}
+
+	contexts := ParseAll(ctx, []types.Parser{urlParser}, "https://youtu.be/abc123", time.Second)
+	if called {
+		t.Error("fetcher should not be called once the context is cancelled")
+	}
+	if len(contexts) != 1 || contexts[0].DetectedType != types.ContentTypeYouTubeURL {
+		t.Errorf("ParseAll() = %v, want the offline YouTube result", contexts)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	if got := Timeout(nil); got != DefaultTimeout {
+		t.Errorf("Timeout(nil) = %v, want %v", got, DefaultTimeout)
+	}
+	if got := Timeout(&types.Config{}); got != DefaultTimeout {
+		t.Errorf("Timeout(empty) = %v, want %v", got, DefaultTimeout)
+	}
+	if got := Timeout(&types.Config{ParseTimeout: 2 * time.Second}); got != 2*time.Second {
+		t.Errorf("Timeout(2s) = %v, want 2s", got)
+	}
+}
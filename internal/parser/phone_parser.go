@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"context"
 	"regexp"
 	"strings"
 
@@ -19,7 +20,7 @@ func (p *PhoneParser) CanHandle(input string) bool {
 	return p.detectPhoneNumber(input) != nil
 }
 
-func (p *PhoneParser) Parse(input string) (*types.ParseContext, error) {
+func (p *PhoneParser) Parse(_ context.Context, input string) (*types.ParseContext, error) {
 	match := p.detectPhoneNumber(input)
 	if match == nil {
 		return nil, nil
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"context"
 	"testing"
 
 	"github.com/erebusbat/markdown-tool/pkg/types"
@@ -288,7 +289,7 @@ func TestPhoneParser_Parse(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ctx, err := parser.Parse(tt.input)
+			ctx, err := parser.Parse(context.Background(), tt.input)
 			if err != nil {
 				t.Fatalf("Parse(%q) error = %v", tt.input, err)
 			}
@@ -355,7 +356,7 @@ func TestPhoneParser_ConfidenceScoring(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ctx, err := parser.Parse(tt.input)
+			ctx, err := parser.Parse(context.Background(), tt.input)
 			if err != nil {
 				t.Fatalf("Parse(%q) error = %v", tt.input, err)
 			}
@@ -403,7 +404,7 @@ func TestPhoneParser_InvalidFormats(t *testing.T) {
 				t.Errorf("CanHandle(%q) = true, want false (should not match invalid format)", input)
 			}
 
-			ctx, err := parser.Parse(input)
+			ctx, err := parser.Parse(context.Background(), input)
 			if err != nil {
 				t.Errorf("Parse(%q) should not error on invalid input, got: %v", input, err)
 			}
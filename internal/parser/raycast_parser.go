@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"context"
 	"net/url"
 	"strings"
 
@@ -26,7 +27,7 @@ func (p *RaycastParser) CanHandle(input string) bool {
 	return err == nil
 }
 
-func (p *RaycastParser) Parse(input string) (*types.ParseContext, error) {
+func (p *RaycastParser) Parse(_ context.Context, input string) (*types.ParseContext, error) {
 	if !p.CanHandle(input) {
 		return nil, nil
 	}
@@ -1,24 +1,40 @@
 package parser
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"net/url"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/erebusbat/markdown-tool/internal/cache"
+	"github.com/erebusbat/markdown-tool/internal/fetch"
+	"github.com/erebusbat/markdown-tool/internal/github"
+	"github.com/erebusbat/markdown-tool/internal/jira"
 	"github.com/erebusbat/markdown-tool/pkg/types"
 )
 
+// oEmbedTimeout bounds a single oEmbed request independently of the caller's
+// deadline so a stalled connection can never hang the tool
+const oEmbedTimeout = 5 * time.Second
+
 type URLParser struct {
 	config              *types.Config
-	youtubeTitleFetcher func(string) string
+	hosts               *github.HostSet
+	cache               *cache.Cache
+	client              *fetch.Client
+	youtubeTitleFetcher func(context.Context, string) (string, error)
 }
 
 func NewURLParser(cfg *types.Config) *URLParser {
-	parser := &URLParser{config: cfg}
+	parser := &URLParser{
+		config: cfg,
+		hosts:  github.NewHostSet(cfg),
+		cache:  cache.FromConfig(cfg),
+		client: fetch.FromConfig(cfg, fetch.Options{Timeout: oEmbedTimeout}),
+	}
 	parser.youtubeTitleFetcher = parser.fetchYouTubeTitleFromOEmbed
 	return parser
 }
@@ -28,7 +44,7 @@ func (p *URLParser) CanHandle(input string) bool {
 	return err == nil && (strings.HasPrefix(input, "http://") || strings.HasPrefix(input, "https://"))
 }
 
-func (p *URLParser) Parse(input string) (*types.ParseContext, error) {
+func (p *URLParser) Parse(ctx context.Context, input string) (*types.ParseContext, error) {
 	if !p.CanHandle(input) {
 		return nil, nil
 	}
@@ -38,7 +54,7 @@ func (p *URLParser) Parse(input string) (*types.ParseContext, error) {
 		return nil, err
 	}
 
-	ctx := &types.ParseContext{
+	parsed := &types.ParseContext{
 		OriginalInput: input,
 		Metadata:      make(map[string]interface{}),
 	}
@@ -46,73 +62,72 @@ func (p *URLParser) Parse(input string) (*types.ParseContext, error) {
 	// Detect specific URL types
 	switch {
 	case p.isGitHubURL(u):
-		ctx.DetectedType = types.ContentTypeGitHubURL
-		ctx.Confidence = 90
-		p.parseGitHubURL(u, ctx)
+		p.parseGitHubURL(u, parsed)
+		if _, ok := parsed.Metadata["anchor"]; ok {
+			parsed.DetectedType = types.ContentTypeGitHubComment
+			parsed.Confidence = 95
+		} else {
+			parsed.DetectedType = types.ContentTypeGitHubURL
+			parsed.Confidence = 90
+		}
 	case p.isJIRAURL(u):
-		if p.isJIRACommentURL(u) {
-			ctx.DetectedType = types.ContentTypeJIRAComment
-			ctx.Confidence = 95
+		p.parseJIRAURL(u, parsed)
+		if _, ok := parsed.Metadata["comment_id"]; ok {
+			parsed.DetectedType = types.ContentTypeJIRAComment
+			parsed.Confidence = 95
 		} else {
-			ctx.DetectedType = types.ContentTypeJIRAURL
-			ctx.Confidence = 90
+			parsed.DetectedType = types.ContentTypeJIRAURL
+			parsed.Confidence = 90
 		}
-		p.parseJIRAURL(u, ctx)
 	case p.isJenkinsURL(u):
-		ctx.DetectedType = types.ContentTypeJenkinsURL
-		ctx.Confidence = 90
-		p.parseJenkinsURL(u, ctx)
+		parsed.DetectedType = types.ContentTypeJenkinsURL
+		parsed.Confidence = 90
+		p.parseJenkinsURL(u, parsed)
 	case p.isYouTubeURL(u):
-		ctx.DetectedType = types.ContentTypeYouTubeURL
-		ctx.Confidence = 90
-		p.parseYouTubeURL(u, ctx)
+		parsed.DetectedType = types.ContentTypeYouTubeURL
+		parsed.Confidence = 90
+		p.parseYouTubeURL(ctx, u, parsed)
 	case p.isCodeCommitURL(u):
-		ctx.DetectedType = types.ContentTypeCodeCommitURL
-		ctx.Confidence = 90
-		p.parseCodeCommitURL(u, ctx)
+		parsed.DetectedType = types.ContentTypeCodeCommitURL
+		parsed.Confidence = 90
+		p.parseCodeCommitURL(u, parsed)
 	case p.isNotionURL(u):
-		ctx.DetectedType = types.ContentTypeNotionURL
-		ctx.Confidence = 85
-		p.parseNotionURL(u, ctx)
+		parsed.DetectedType = types.ContentTypeNotionURL
+		parsed.Confidence = 85
+		p.parseNotionURL(u, parsed)
 	case p.isMiniMaxURL(u):
-		ctx.DetectedType = types.ContentTypeMiniMaxURL
-		ctx.Confidence = 90
-		p.parseMiniMaxURL(u, ctx)
+		parsed.DetectedType = types.ContentTypeMiniMaxURL
+		parsed.Confidence = 90
+		p.parseMiniMaxURL(u, parsed)
 	case p.isGeminiURL(u):
-		ctx.DetectedType = types.ContentTypeGeminiURL
-		ctx.Confidence = 90
-		p.parseGeminiURL(u, ctx)
+		parsed.DetectedType = types.ContentTypeGeminiURL
+		parsed.Confidence = 90
+		p.parseGeminiURL(u, parsed)
 	case p.isCircleCIURL(u):
-		ctx.DetectedType = types.ContentTypeCircleCI
-		ctx.Confidence = 90
-		p.parseCircleCIURL(u, ctx)
+		parsed.DetectedType = types.ContentTypeCircleCI
+		parsed.Confidence = 90
+		p.parseCircleCIURL(u, parsed)
 	case p.isChatGPTURL(u):
-		ctx.DetectedType = types.ContentTypeChatGPT
-		ctx.Confidence = 90
-		p.parseChatGPTURL(u, ctx)
+		parsed.DetectedType = types.ContentTypeChatGPT
+		parsed.Confidence = 90
+		p.parseChatGPTURL(u, parsed)
 	default:
-		ctx.DetectedType = types.ContentTypeURL
-		ctx.Confidence = 50
-		ctx.Metadata["domain"] = u.Host
+		parsed.DetectedType = types.ContentTypeURL
+		parsed.Confidence = 50
+		parsed.Metadata["domain"] = u.Host
 	}
 
-	return ctx, nil
+	return parsed, nil
 }
 
 func (p *URLParser) isGitHubURL(u *url.URL) bool {
-	return u.Host == "github.com"
+	_, ok := p.hosts.Lookup(u.Host)
+	return ok
 }
 
 func (p *URLParser) isJIRAURL(u *url.URL) bool {
-	if p.config.JIRA.Domain == "" {
-		return false
-	}
-	jiraURL, _ := url.Parse(p.config.JIRA.Domain)
-	return u.Host == jiraURL.Host
-}
-
-func (p *URLParser) isJIRACommentURL(u *url.URL) bool {
-	return p.isJIRAURL(u) && u.Query().Get("focusedCommentId") != ""
+	_, ok := jira.ForURL(p.config, u)
+	return ok
 }
 
 func (p *URLParser) isJenkinsURL(u *url.URL) bool {
@@ -206,41 +221,28 @@ func (p *URLParser) parseMiniMaxURL(u *url.URL, ctx *types.ParseContext) {
 }
 
 func (p *URLParser) parseGitHubURL(u *url.URL, ctx *types.ParseContext) {
-	// Extract org/repo and optionally issue/PR number from GitHub URLs
-	// Path formats:
-	// - /org/repo (simple repository URL)
-	// - /org/repo/pull/123 or /org/repo/issues/123 (issue/PR URLs)
-	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
-	if len(parts) >= 2 {
-		org := parts[0]
-		repo := parts[1]
-
-		ctx.Metadata["org"] = org
-		ctx.Metadata["repo"] = repo
-
-		// If there are 4+ parts, extract issue/PR information
-		if len(parts) >= 4 {
-			issueType := parts[2] // "pull" or "issues"
-			number := parts[3]
+	if host, ok := p.hosts.Lookup(u.Host); ok {
+		ctx.Metadata["host"] = host.Name
+	}
 
-			ctx.Metadata["type"] = issueType
-			ctx.Metadata["number"] = number
-		}
+	// Classify the path: issue, pull request, file, release, Actions run...
+	route, ok := github.ParseRoute(u)
+	if !ok {
+		return
+	}
+	for key, value := range route.Metadata() {
+		ctx.Metadata[key] = value
 	}
 }
 
 func (p *URLParser) parseJIRAURL(u *url.URL, ctx *types.ParseContext) {
-	// Extract JIRA issue key from URL
-	// Path format: /browse/PROJ-123
-	re := regexp.MustCompile(`/browse/([A-Z]+-\d+)`)
-	matches := re.FindStringSubmatch(u.Path)
-	if len(matches) > 1 {
-		ctx.Metadata["issue_key"] = matches[1]
+	instance, ok := jira.ForURL(p.config, u)
+	if !ok {
+		return
 	}
-
-	// Check if it's a comment URL
-	if commentId := u.Query().Get("focusedCommentId"); commentId != "" {
-		ctx.Metadata["comment_id"] = commentId
+	ctx.Metadata["domain"] = instance.Domain
+	for key, value := range instance.ParseRoute(u).Metadata() {
+		ctx.Metadata[key] = value
 	}
 }
 
@@ -271,20 +273,20 @@ func (p *URLParser) parseJenkinsURL(u *url.URL, ctx *types.ParseContext) {
 	}
 }
 
-func (p *URLParser) parseYouTubeURL(u *url.URL, ctx *types.ParseContext) {
+func (p *URLParser) parseYouTubeURL(ctx context.Context, u *url.URL, parsed *types.ParseContext) {
 	if u.Path == "/playlist" {
 		playlistID := u.Query().Get("list")
 		if playlistID == "" {
 			return
 		}
 
-		ctx.Metadata["youtube_type"] = "playlist"
-		ctx.Metadata["playlist_id"] = playlistID
+		parsed.Metadata["youtube_type"] = "playlist"
+		parsed.Metadata["playlist_id"] = playlistID
 
 		playlistURL := fmt.Sprintf("https://www.youtube.com/playlist?list=%s", url.QueryEscape(playlistID))
-		title := p.fetchYouTubeTitleByURL(playlistURL)
+		title := p.fetchYouTubeTitleByURL(ctx, playlistURL)
 		if title != "" {
-			ctx.Metadata["title"] = title
+			parsed.Metadata["title"] = title
 		}
 		return
 	}
@@ -309,59 +311,55 @@ func (p *URLParser) parseYouTubeURL(u *url.URL, ctx *types.ParseContext) {
 		return
 	}
 
-	ctx.Metadata["youtube_type"] = "video"
-	ctx.Metadata["video_id"] = videoID
+	parsed.Metadata["youtube_type"] = "video"
+	parsed.Metadata["video_id"] = videoID
 
 	// Fetch video title using YouTube oEmbed API (no API key required)
-	title := p.fetchYouTubeTitle(videoID)
+	title := p.fetchYouTubeTitle(ctx, videoID)
 	if title != "" {
-		ctx.Metadata["title"] = title
+		parsed.Metadata["title"] = title
 	}
 }
 
-func (p *URLParser) fetchYouTubeTitle(videoID string) string {
+func (p *URLParser) fetchYouTubeTitle(ctx context.Context, videoID string) string {
 	videoURL := fmt.Sprintf("https://www.youtube.com/watch?v=%s", videoID)
-	return p.fetchYouTubeTitleByURL(videoURL)
+	return p.fetchYouTubeTitleByURL(ctx, videoURL)
 }
 
-func (p *URLParser) fetchYouTubeTitleByURL(targetURL string) string {
+func (p *URLParser) fetchYouTubeTitleByURL(ctx context.Context, targetURL string) string {
 	if p.youtubeTitleFetcher == nil {
 		return ""
 	}
 
-	return p.youtubeTitleFetcher(targetURL)
+	// Degrade to the offline result (no title) if the deadline has already passed
+	if ctx.Err() != nil {
+		return ""
+	}
+
+	title, _ := p.cache.Fetch(ctx, cache.SourceYouTube, targetURL, func(ctx context.Context) (string, error) {
+		return p.youtubeTitleFetcher(ctx, targetURL)
+	})
+	return title
 }
 
-func (p *URLParser) fetchYouTubeTitleFromOEmbed(targetURL string) string {
+func (p *URLParser) fetchYouTubeTitleFromOEmbed(ctx context.Context, targetURL string) (string, error) {
 	// Use YouTube oEmbed API to get video or playlist title
 	oembedURL := fmt.Sprintf("https://www.youtube.com/oembed?url=%s&format=json", url.QueryEscape(targetURL))
 
-	resp, err := http.Get(oembedURL)
+	resp, err := p.client.Get(ctx, oembedURL)
 	if err != nil {
-		return ""
-	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
-
-	if resp.StatusCode != http.StatusOK {
-		return ""
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return ""
+		return "", err
 	}
 
 	var result struct {
 		Title string `json:"title"`
 	}
 
-	if err := json.Unmarshal(body, &result); err != nil {
-		return ""
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		return "", err
 	}
 
-	return result.Title
+	return result.Title, nil
 }
 
 func (p *URLParser) parseCodeCommitURL(u *url.URL, ctx *types.ParseContext) {
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"context"
 	"testing"
 
 	"github.com/erebusbat/markdown-tool/pkg/types"
@@ -107,7 +108,7 @@ func TestURLParser_Parse_GitHub(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ctx, err := parser.Parse(tt.input)
+			ctx, err := parser.Parse(context.Background(), tt.input)
 			if err != nil {
 				t.Fatalf("Parse() error = %v", err)
 			}
@@ -149,6 +150,43 @@ func TestURLParser_Parse_GitHub(t *testing.T) {
 	}
 }
 
+func TestURLParser_Parse_GitHubHosts(t *testing.T) {
+	cfg := &types.Config{
+		GitHub: types.GitHubConfig{
+			Hosts: []types.GitHubHostConfig{{Host: "github.acme.com"}},
+		},
+	}
+	parser := NewURLParser(cfg)
+
+	tests := []struct {
+		name         string
+		input        string
+		expectedType types.ContentType
+		expectedHost interface{}
+	}{
+		{"Public host", "https://github.com/acme/api/pull/1", types.ContentTypeGitHubURL, "github.com"},
+		{"www alias", "https://www.github.com/acme/api/pull/1", types.ContentTypeGitHubURL, "github.com"},
+		{"Enterprise host", "https://github.acme.com/platform/api/issues/7", types.ContentTypeGitHubURL, "github.acme.com"},
+		{"Unconfigured host", "https://github.other.com/platform/api/issues/7", types.ContentTypeURL, nil},
+		{"Comment anchor", "https://github.acme.com/platform/api/pull/7#issuecomment-1", types.ContentTypeGitHubComment, "github.acme.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := parser.Parse(context.Background(), tt.input)
+			if err != nil || ctx == nil {
+				t.Fatalf("Parse() = %v, %v", ctx, err)
+			}
+			if ctx.DetectedType != tt.expectedType {
+				t.Errorf("DetectedType = %v, want %v", ctx.DetectedType, tt.expectedType)
+			}
+			if host := ctx.Metadata["host"]; host != tt.expectedHost {
+				t.Errorf("Metadata[host] = %v, want %v", host, tt.expectedHost)
+			}
+		})
+	}
+}
+
 func TestURLParser_Parse_JIRA(t *testing.T) {
 	cfg := &types.Config{
 		JIRA: types.JIRAConfig{
@@ -178,11 +216,29 @@ func TestURLParser_Parse_JIRA(t *testing.T) {
 			expectedKey:  "PLAT-192",
 			hasComment:   true,
 		},
+		{
+			name:         "JIRA Cloud comment",
+			input:        "https://companycam.atlassian.net/browse/PLAT-192?focusedId=20266",
+			expectedType: types.ContentTypeJIRAComment,
+			expectedKey:  "PLAT-192",
+			hasComment:   true,
+		},
+		{
+			name:         "Issue selected on a board",
+			input:        "https://companycam.atlassian.net/jira/software/projects/PLAT/boards/12?selectedIssue=PLAT-192",
+			expectedType: types.ContentTypeJIRAURL,
+			expectedKey:  "PLAT-192",
+		},
+		{
+			name:         "Board",
+			input:        "https://companycam.atlassian.net/jira/software/c/projects/PLAT/boards/12",
+			expectedType: types.ContentTypeJIRAURL,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ctx, err := parser.Parse(tt.input)
+			ctx, err := parser.Parse(context.Background(), tt.input)
 			if err != nil {
 				t.Fatalf("Parse() error = %v", err)
 			}
@@ -195,7 +251,7 @@ func TestURLParser_Parse_JIRA(t *testing.T) {
 				t.Errorf("DetectedType = %v, want %v", ctx.DetectedType, tt.expectedType)
 			}
 
-			if key := ctx.Metadata["issue_key"]; key != tt.expectedKey {
+			if key, _ := ctx.Metadata["issue_key"].(string); key != tt.expectedKey {
 				t.Errorf("Metadata[issue_key] = %v, want %v", key, tt.expectedKey)
 			}
 
@@ -208,6 +264,78 @@ func TestURLParser_Parse_JIRA(t *testing.T) {
 	}
 }
 
+func TestURLParser_Parse_JIRAInstances(t *testing.T) {
+	cfg := &types.Config{
+		JIRA: types.JIRAConfig{
+			Domain:   "https://companycam.atlassian.net",
+			Projects: []string{"PLAT"},
+			Instances: []types.JIRAInstanceConfig{
+				{Domain: "https://jira.client.com/jira", Projects: []string{"CLI"}, Prefix: "Client"},
+			},
+		},
+	}
+	parser := NewURLParser(cfg)
+
+	tests := []struct {
+		name           string
+		input          string
+		expectedType   types.ContentType
+		expectedKey    string
+		expectedDomain string
+	}{
+		{
+			name:           "Cloud issue view",
+			input:          "https://companycam.atlassian.net/jira/software/c/projects/PLAT/issues/PLAT-192",
+			expectedType:   types.ContentTypeJIRAURL,
+			expectedKey:    "PLAT-192",
+			expectedDomain: "https://companycam.atlassian.net",
+		},
+		{
+			name:           "Server issue under a context path",
+			input:          "https://jira.client.com/jira/browse/CLI-45",
+			expectedType:   types.ContentTypeJIRAURL,
+			expectedKey:    "CLI-45",
+			expectedDomain: "https://jira.client.com/jira",
+		},
+		{
+			name:           "Server project issue view",
+			input:          "https://jira.client.com/jira/projects/CLI/issues/CLI-45?filter=allopenissues",
+			expectedType:   types.ContentTypeJIRAURL,
+			expectedKey:    "CLI-45",
+			expectedDomain: "https://jira.client.com/jira",
+		},
+		{
+			name:         "Same host outside the context path",
+			input:        "https://jira.client.com/wiki/display/CLI",
+			expectedType: types.ContentTypeURL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := parser.Parse(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if ctx == nil {
+				t.Fatal("Parse() returned nil context")
+			}
+			if ctx.DetectedType != tt.expectedType {
+				t.Errorf("DetectedType = %v, want %v", ctx.DetectedType, tt.expectedType)
+			}
+			if tt.expectedKey == "" {
+				return
+			}
+			if key := ctx.Metadata["issue_key"]; key != tt.expectedKey {
+				t.Errorf("Metadata[issue_key] = %v, want %v", key, tt.expectedKey)
+			}
+			if domain := ctx.Metadata["domain"]; domain != tt.expectedDomain {
+				t.Errorf("Metadata[domain] = %v, want %v", domain, tt.expectedDomain)
+			}
+		})
+	}
+}
+
 func TestURLParser_Parse_Jenkins(t *testing.T) {
 	cfg := &types.Config{
 		Jenkins: types.JenkinsConfig{
@@ -269,7 +397,7 @@ func TestURLParser_Parse_Jenkins(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ctx, err := parser.Parse(tt.input)
+			ctx, err := parser.Parse(context.Background(), tt.input)
 			if err != nil {
 				t.Fatalf("Parse() error = %v", err)
 			}
@@ -330,7 +458,7 @@ func TestURLParser_Parse_CodeCommit(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ctx, err := parser.Parse(tt.input)
+			ctx, err := parser.Parse(context.Background(), tt.input)
 			if err != nil {
 				t.Fatalf("Parse() error = %v", err)
 			}
@@ -361,7 +489,7 @@ func TestURLParser_Parse_Notion(t *testing.T) {
 	parser := NewURLParser(cfg)
 
 	input := "https://www.notion.so/companycam/VS-Code-Setup-for-Standard-rb-RubyLSP-654a6b070ae74ac3ad400c6d571507c0"
-	ctx, err := parser.Parse(input)
+	ctx, err := parser.Parse(context.Background(), input)
 
 	if err != nil {
 		t.Fatalf("Parse() error = %v", err)
@@ -384,14 +512,14 @@ func TestURLParser_Parse_Notion(t *testing.T) {
 func TestURLParser_Parse_YouTube(t *testing.T) {
 	cfg := &types.Config{}
 	parser := NewURLParser(cfg)
-	parser.youtubeTitleFetcher = func(targetURL string) string {
+	parser.youtubeTitleFetcher = func(_ context.Context, targetURL string) (string, error) {
 		switch targetURL {
 		case "https://www.youtube.com/watch?v=fkT41ooKBuY":
-			return "Stop overpaying for OpenAI: Multi-model routing guide"
+			return "Stop overpaying for OpenAI: Multi-model routing guide", nil
 		case "https://www.youtube.com/playlist?list=PLCC34OHNcOtpcgR9LEYSdi9r7XIbpkpK1":
-			return "Deep Learning With PyTorch"
+			return "Deep Learning With PyTorch", nil
 		default:
-			return ""
+			return "", nil
 		}
 	}
 
@@ -421,7 +549,7 @@ func TestURLParser_Parse_YouTube(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ctx, err := parser.Parse(tt.input)
+			ctx, err := parser.Parse(context.Background(), tt.input)
 			if err != nil {
 				t.Fatalf("Parse() error = %v", err)
 			}
@@ -462,7 +590,7 @@ func TestURLParser_Parse_Generic(t *testing.T) {
 	parser := NewURLParser(cfg)
 
 	input := "http://ww3.domain.tld/path/to/document?query=value#anchor"
-	ctx, err := parser.Parse(input)
+	ctx, err := parser.Parse(context.Background(), input)
 
 	if err != nil {
 		t.Fatalf("Parse() error = %v", err)
@@ -517,7 +645,7 @@ func TestURLParser_Parse_ChatGPT(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ctx, err := p.Parse(tt.input)
+			ctx, err := p.Parse(context.Background(), tt.input)
 			if err != nil {
 				t.Fatalf("Parse() error = %v", err)
 			}
@@ -581,7 +709,7 @@ func TestURLParser_Parse_CircleCI(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ctx, err := p.Parse(tt.input)
+			ctx, err := p.Parse(context.Background(), tt.input)
 			if err != nil {
 				t.Fatalf("Parse() error = %v", err)
 			}
@@ -666,7 +794,7 @@ func TestURLParser_Parse_Gemini(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ctx, err := p.Parse(tt.input)
+			ctx, err := p.Parse(context.Background(), tt.input)
 			if err != nil {
 				t.Fatalf("Parse() error = %v", err)
 			}
@@ -691,3 +819,49 @@ func TestURLParser_Parse_Gemini(t *testing.T) {
 		})
 	}
 }
+
+func TestURLParser_Parse_YouTubeUsesCache(t *testing.T) {
+	cfg := &types.Config{Cache: types.CacheConfig{Dir: t.TempDir()}}
+	parser := NewURLParser(cfg)
+
+	calls := 0
+	parser.youtubeTitleFetcher = func(_ context.Context, targetURL string) (string, error) {
+		calls++
+		return "Cached Video", nil
+	}
+
+	for i := 0; i < 2; i++ {
+		ctx, err := parser.Parse(context.Background(), "https://youtu.be/abc123")
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if ctx.Metadata["title"] != "Cached Video" {
+			t.Errorf("Metadata[title] = %v, want Cached Video", ctx.Metadata["title"])
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("youtubeTitleFetcher called %d times, want 1", calls)
+	}
+}
+
+func TestURLParser_Parse_YouTubeNetworkOff(t *testing.T) {
+	cfg := &types.Config{
+		Cache:   types.CacheConfig{Dir: t.TempDir()},
+		Network: types.NetworkConfig{Mode: types.NetworkModeOff},
+	}
+	parser := NewURLParser(cfg)
+
+	ctx, err := parser.Parse(context.Background(), "https://www.youtube.com/watch?v=abc123")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if _, ok := ctx.Metadata["title"]; ok {
+		t.Errorf("Metadata[title] = %v, want no title with the network off", ctx.Metadata["title"])
+	}
+
+	// A policy denial must not be remembered as a failed lookup
+	if entries := parser.cache.Lookup("https://www.youtube.com/watch?v=abc123"); len(entries) != 0 {
+		t.Errorf("cache entries = %+v, want none", entries)
+	}
+}
@@ -0,0 +1,73 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/erebusbat/markdown-tool/internal/enricher"
+	"github.com/erebusbat/markdown-tool/internal/parser"
+	"github.com/erebusbat/markdown-tool/internal/writer"
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+// Pipeline is the Parse → Enrich → Vote → Write chain built from one
+// configuration. It is immutable once built, so a long-running process can
+// swap in a new Pipeline on reload while transforms on the old one finish.
+type Pipeline struct {
+	config    *types.Config
+	parsers   []types.Parser
+	enrichers []types.Enricher
+	writers   []types.Writer
+}
+
+// New builds the parsers, enrichers and writers for cfg
+func New(cfg *types.Config) *Pipeline {
+	return &Pipeline{
+		config:    cfg,
+		parsers:   parser.GetParsers(cfg),
+		enrichers: enricher.GetEnrichers(cfg),
+		writers:   writer.GetWriters(cfg),
+	}
+}
+
+// Config returns the configuration the pipeline was built from
+func (p *Pipeline) Config() *types.Config {
+	return p.config
+}
+
+// Result is the outcome of one transformation
+type Result struct {
+	Output string
+	// Type is the content type of the parse result that was written, or
+	// ContentTypeUnknown when the input was passed through verbatim
+	Type types.ContentType
+	// Writer names the writer that produced Output, if any
+	Writer string
+}
+
+// Transform converts input to markdown. Input that no writer wants is
+// returned verbatim.
+func (p *Pipeline) Transform(ctx context.Context, input string) (string, error) {
+	result, err := p.Run(ctx, input)
+	return result.Output, err
+}
+
+// Run converts input to markdown and reports how it was handled
+func (p *Pipeline) Run(ctx context.Context, input string) (Result, error) {
+	contexts := parser.ParseAll(ctx, p.parsers, input, parser.Timeout(p.config))
+
+	// Optionally enrich parsed content (e.g. page titles) over the network
+	enricher.EnrichAll(ctx, p.enrichers, contexts, enricher.Timeout(p.config))
+
+	bestWriter, bestScore := writer.Vote(p.writers, contexts)
+	if bestWriter == nil || bestScore == 0 || len(contexts) == 0 {
+		// No writer wants to handle this, output verbatim
+		return Result{Output: input, Type: types.ContentTypeUnknown}, nil
+	}
+
+	output, err := bestWriter.Write(contexts[0])
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to write output: %w", err)
+	}
+	return Result{Output: output, Type: contexts[0].DetectedType, Writer: bestWriter.GetName()}, nil
+}
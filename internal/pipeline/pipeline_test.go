@@ -0,0 +1,45 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/erebusbat/markdown-tool/pkg/types"
+)
+
+func TestPipeline_Transform(t *testing.T) {
+	cfg := &types.Config{
+		JIRA: types.JIRAConfig{
+			Domain:   "https://companycam.atlassian.net",
+			Projects: []string{"PLAT"},
+		},
+		Network: types.NetworkConfig{Mode: types.NetworkModeOff},
+	}
+	p := New(cfg)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"JIRA key", "PLAT-123", "[PLAT-123](https://companycam.atlassian.net/browse/PLAT-123)"},
+		{"GitHub PR", "https://github.com/org/repo/pull/42", "[org/repo#42](https://github.com/org/repo/pull/42)"},
+		{"Unconfigured project is verbatim", "OTHER-1", "OTHER-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := p.Transform(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Transform() error = %v", err)
+			}
+			if output != tt.expected {
+				t.Errorf("Transform(%q) = %q, want %q", tt.input, output, tt.expected)
+			}
+		})
+	}
+
+	if p.Config() != cfg {
+		t.Error("Config() should return the configuration the pipeline was built from")
+	}
+}
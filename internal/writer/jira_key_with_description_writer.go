@@ -37,7 +37,6 @@ func (w *JIRAKeyWithDescriptionWriter) Write(ctx *types.ParseContext) (string, e
 		return ctx.OriginalInput, nil
 	}
 
-	// Build JIRA URL
-	jiraURL := fmt.Sprintf("%s/browse/%s", w.config.JIRA.Domain, issueKey)
-	return fmt.Sprintf("[%s: %s](%s)", issueKey, description, jiraURL), nil
+	instance := jiraInstance(w.config, ctx, issueKey)
+	return fmt.Sprintf("[%s: %s](%s)", instance.DisplayKey(issueKey), description, instance.IssueURL(issueKey)), nil
 }
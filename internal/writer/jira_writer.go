@@ -3,6 +3,7 @@ package writer
 import (
 	"fmt"
 
+	"github.com/erebusbat/markdown-tool/internal/jira"
 	"github.com/erebusbat/markdown-tool/pkg/types"
 )
 
@@ -35,7 +36,20 @@ func (w *JIRAWriter) Write(ctx *types.ParseContext) (string, error) {
 		return ctx.OriginalInput, nil
 	}
 
-	// Build JIRA URL
-	jiraURL := fmt.Sprintf("%s/browse/%s", w.config.JIRA.Domain, issueKey)
-	return fmt.Sprintf("[%s](%s)", issueKey, jiraURL), nil
+	instance := jiraInstance(w.config, ctx, issueKey)
+	return fmt.Sprintf("[%s](%s)", instance.DisplayKey(issueKey), instance.IssueURL(issueKey)), nil
+}
+
+// jiraInstance returns the instance the parser recorded for ctx, or the one
+// listing the project of issueKey when none was
+func jiraInstance(cfg *types.Config, ctx *types.ParseContext, issueKey string) jira.Instance {
+	if domain, _ := ctx.Metadata["domain"].(string); domain != "" {
+		if instance, ok := jira.ForDomain(cfg, domain); ok {
+			return instance
+		}
+	}
+	if instance, ok := jira.ForKey(cfg, issueKey); ok {
+		return instance
+	}
+	return jira.Instance{Domain: cfg.JIRA.Domain}
 }
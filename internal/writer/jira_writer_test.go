@@ -39,6 +39,9 @@ func TestJIRAWriter_Write(t *testing.T) {
 	cfg := &types.Config{
 		JIRA: types.JIRAConfig{
 			Domain: "https://companycam.atlassian.net",
+			Instances: []types.JIRAInstanceConfig{
+				{Domain: "https://jira.client.com/jira", Projects: []string{"CLI"}, Prefix: "Client"},
+			},
 		},
 	}
 	writer := NewJIRAWriter(cfg)
@@ -70,6 +73,17 @@ func TestJIRAWriter_Write(t *testing.T) {
 			originalInput:  "SPEED-456",
 			expectedOutput: "[SPEED-456](https://companycam.atlassian.net/browse/SPEED-456)",
 		},
+		{
+			name:        "Key of another instance",
+			contentType: types.ContentTypeJIRAKey,
+			metadata: map[string]interface{}{
+				"issue_key": "CLI-45",
+				"project":   "CLI",
+				"domain":    "https://jira.client.com/jira",
+			},
+			originalInput:  "CLI-45",
+			expectedOutput: "[Client CLI-45](https://jira.client.com/jira/browse/CLI-45)",
+		},
 		{
 			name:           "Non-JIRA content type returns original",
 			contentType:    types.ContentTypeURL,
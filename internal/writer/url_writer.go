@@ -6,15 +6,18 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/erebusbat/markdown-tool/internal/github"
+	"github.com/erebusbat/markdown-tool/internal/jira"
 	"github.com/erebusbat/markdown-tool/pkg/types"
 )
 
 type URLWriter struct {
 	config *types.Config
+	hosts  *github.HostSet
 }
 
 func NewURLWriter(cfg *types.Config) *URLWriter {
-	return &URLWriter{config: cfg}
+	return &URLWriter{config: cfg, hosts: github.NewHostSet(cfg)}
 }
 
 func (w *URLWriter) GetName() string {
@@ -25,8 +28,16 @@ func (w *URLWriter) Vote(ctx *types.ParseContext) int {
 	switch ctx.DetectedType {
 	case types.ContentTypeGitHubURL:
 		return 90
+	case types.ContentTypeGitHubComment:
+		return 95
 	case types.ContentTypeGitHubLong:
 		return 95
+	case types.ContentTypeGitHubList:
+		return 95
+	case types.ContentTypeGitRemote:
+		return 90
+	case types.ContentTypeGitBranch:
+		return 90
 	case types.ContentTypeJIRAURL:
 		return 90
 	case types.ContentTypeJIRAComment:
@@ -58,10 +69,16 @@ func (w *URLWriter) Vote(ctx *types.ParseContext) int {
 
 func (w *URLWriter) Write(ctx *types.ParseContext) (string, error) {
 	switch ctx.DetectedType {
-	case types.ContentTypeGitHubURL:
+	case types.ContentTypeGitHubURL, types.ContentTypeGitHubComment:
 		return w.writeGitHubURL(ctx)
 	case types.ContentTypeGitHubLong:
 		return w.writeGitHubLongURL(ctx)
+	case types.ContentTypeGitHubList:
+		return w.writeGitHubList(ctx)
+	case types.ContentTypeGitRemote:
+		return w.writeGitRemote(ctx)
+	case types.ContentTypeGitBranch:
+		return w.writeGitBranch(ctx)
 	case types.ContentTypeJIRAURL:
 		return w.writeJIRAURL(ctx)
 	case types.ContentTypeJIRAComment:
@@ -94,39 +111,22 @@ func (w *URLWriter) Write(ctx *types.ParseContext) (string, error) {
 func (w *URLWriter) writeGitHubURL(ctx *types.ParseContext) (string, error) {
 	org, _ := ctx.Metadata["org"].(string)
 	repo, _ := ctx.Metadata["repo"].(string)
-	number, _ := ctx.Metadata["number"].(string)
 
 	if org == "" || repo == "" {
 		return w.writeGenericURL(ctx)
 	}
 
 	// Apply organization/repository mappings if configured
-	orgRepo := fmt.Sprintf("%s/%s", org, repo)
-	// Try case-insensitive lookup since Viper lowercases map keys
-	for key, mapped := range w.config.GitHub.Mappings {
-		if strings.EqualFold(key, orgRepo) {
-			orgRepo = mapped
-			break
-		}
-	}
+	orgRepo := w.githubHost(ctx, org, repo).DisplayName(org, repo)
 
-	// If there's an issue/PR/commit number, format as org/repo#number
-	// For commits, truncate hash to 7 characters in link text
-	// Otherwise, format as org/repo for simple repository URLs
-	var linkText string
-	if number != "" {
-		issueType, _ := ctx.Metadata["type"].(string)
-		if issueType == "commit" && len(number) > 7 {
-			// Truncate commit hash to 7 characters for display
-			linkText = fmt.Sprintf("%s#%s", orgRepo, number[:7])
-		} else {
-			linkText = fmt.Sprintf("%s#%s", orgRepo, number)
-		}
-	} else {
-		linkText = orgRepo
-	}
+	linkText := githubLinkText(orgRepo, ctx.Metadata)
 
-	return fmt.Sprintf("[%s](%s)", linkText, ctx.OriginalInput), nil
+	// Shorthand references carry the URL they expand to
+	link := ctx.OriginalInput
+	if expanded, _ := ctx.Metadata["url"].(string); expanded != "" {
+		link = expanded
+	}
+	return fmt.Sprintf("[%s](%s)", linkText, link), nil
 }
 
 func (w *URLWriter) writeGitHubLongURL(ctx *types.ParseContext) (string, error) {
@@ -147,30 +147,308 @@ func (w *URLWriter) writeGitHubLongURL(ctx *types.ParseContext) (string, error)
 	}
 
 	// Apply organization/repository mappings if configured
-	orgRepo := fmt.Sprintf("%s/%s", org, repo)
-	// Try case-insensitive lookup since Viper lowercases map keys
-	for key, mapped := range w.config.GitHub.Mappings {
-		if strings.EqualFold(key, orgRepo) {
-			orgRepo = mapped
-			break
-		}
-	}
+	host := w.githubHost(ctx, org, repo)
+	orgRepo := host.DisplayName(org, repo)
 
-	// Build the GitHub URL
-	githubURL := fmt.Sprintf("https://github.com/%s/%s/%s/%s", org, repo, issueType, number)
+	// Build the GitHub URL on the host the issue came from
+	githubURL := host.URL(org, repo, issueType, number)
 
 	// Create the link text with org/repo#number: title format
 	linkText := fmt.Sprintf("%s#%s: %s", orgRepo, number, title)
-	return fmt.Sprintf("[%s](%s)", linkText, githubURL), nil
+	link := fmt.Sprintf("[%s](%s)", linkText, githubURL)
+	if w.config.GitHub.PRDetails {
+		if details := githubDetails(ctx.Metadata); details != "" {
+			link += " — " + details
+		}
+	}
+	return link, nil
 }
 
-func (w *URLWriter) writeJIRAURL(ctx *types.ParseContext) (string, error) {
+// writeGitHubList writes a bullet list with a link per issue or pull request.
+// With pr_details each entry is followed by its state and labels.
+func (w *URLWriter) writeGitHubList(ctx *types.ParseContext) (string, error) {
+	items, _ := ctx.Metadata["items"].([]map[string]string)
+	if len(items) == 0 {
+		return ctx.OriginalInput, nil
+	}
+
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		host := w.hosts.ForRepo(item["org"], item["repo"])
+		if named, ok := w.hosts.Lookup(item["host"]); ok {
+			host = named
+		}
+
+		orgRepo := host.DisplayName(item["org"], item["repo"])
+		link := fmt.Sprintf("[%s#%s: %s](%s)", orgRepo, item["number"], item["title"], host.URL(item["org"], item["repo"], item["type"], item["number"]))
+		if w.config.GitHub.PRDetails {
+			details := make([]string, 0, 2)
+			if item["state"] != "" {
+				details = append(details, item["state"])
+			}
+			if item["labels"] != "" {
+				details = append(details, item["labels"])
+			}
+			if len(details) > 0 {
+				link += " — " + strings.Join(details, " · ")
+			}
+		}
+		lines = append(lines, "- "+link)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// githubDetails summarises the state, people and branches of an issue or
+// pull request, e.g. "merged by @alice, feature-x → main"
+func githubDetails(metadata map[string]interface{}) string {
+	field := func(key string) string {
+		value, _ := metadata[key].(string)
+		return value
+	}
+
+	parts := make([]string, 0, 2)
+	state, author := field("state"), field("author")
+	switch {
+	case state == "merged" && field("merged_by") != "":
+		parts = append(parts, "merged by @"+field("merged_by"))
+	case state == "open" && author != "":
+		parts = append(parts, "opened by @"+author)
+	case state == "draft" && author != "":
+		parts = append(parts, "draft by @"+author)
+	case state != "":
+		parts = append(parts, state)
+	case author != "":
+		parts = append(parts, "by @"+author)
+	}
+
+	if head, base := field("head"), field("base"); head != "" && base != "" {
+		parts = append(parts, fmt.Sprintf("%s → %s", head, base))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// githubLinkText describes what a GitHub URL points at, e.g.
+// "org/repo#123", "org/repo:cmd/root.go#L10-L20@main" or
+// "org/repo Actions run 123 (job 456)"
+func githubLinkText(orgRepo string, metadata map[string]interface{}) string {
+	field := func(key string) string {
+		value, _ := metadata[key].(string)
+		return value
+	}
+	number := field("number")
+	ref := field("ref")
+	path := field("path")
+
+	switch field("type") {
+	case github.KindCommit:
+		// Truncate commit hash to 7 characters for display
+		if len(number) > 7 {
+			number = number[:7]
+		}
+		return fmt.Sprintf("%s#%s", orgRepo, number)
+	case github.KindPulls:
+		return orgRepo + " pull requests"
+	case github.KindIssues:
+		if number == "" {
+			return orgRepo + " issues"
+		}
+	case github.KindBlob, github.KindBlame:
+		text := orgRepo + ":" + path
+		if lines := field("lines"); lines != "" {
+			text += "#" + lines
+		}
+		text += "@" + ref
+		if field("type") == github.KindBlame {
+			text += " (blame)"
+		}
+		return text
+	case github.KindTree:
+		if path == "" {
+			return orgRepo + "@" + ref
+		}
+		return fmt.Sprintf("%s:%s@%s", orgRepo, path, ref)
+	case github.KindCommits:
+		if ref == "" {
+			return orgRepo + " commits"
+		}
+		return fmt.Sprintf("%s commits@%s", orgRepo, ref)
+	case github.KindCompare:
+		if base := field("base"); base != "" {
+			return fmt.Sprintf("%s compare %s...%s", orgRepo, base, field("head"))
+		}
+		return fmt.Sprintf("%s compare %s", orgRepo, field("head"))
+	case github.KindRelease:
+		tag := field("tag")
+		if tag == "" {
+			return orgRepo + " latest release"
+		}
+		text := fmt.Sprintf("%s release %s", orgRepo, tag)
+		if asset := field("asset"); asset != "" {
+			text += fmt.Sprintf(" (%s)", asset)
+		}
+		return text
+	case github.KindReleases:
+		return orgRepo + " releases"
+	case github.KindTags:
+		return orgRepo + " tags"
+	case github.KindActions:
+		return orgRepo + " Actions"
+	case github.KindRun:
+		text := fmt.Sprintf("%s Actions run %s", orgRepo, field("run_id"))
+		if job := field("job_id"); job != "" {
+			text += fmt.Sprintf(" (job %s)", job)
+		} else if attempt := field("attempt"); attempt != "" {
+			text += fmt.Sprintf(" (attempt %s)", attempt)
+		}
+		return text
+	case github.KindWorkflow:
+		return fmt.Sprintf("%s Actions workflow %s", orgRepo, field("workflow"))
+	case github.KindDiscussion:
+		return githubAnchorText(fmt.Sprintf("%s discussion #%s", orgRepo, number), metadata)
+	case github.KindDiscussions:
+		return orgRepo + " discussions"
+	case github.KindWiki:
+		if page := field("page"); page != "" {
+			return fmt.Sprintf("%s wiki: %s", orgRepo, strings.ReplaceAll(page, "-", " "))
+		}
+		return orgRepo + " wiki"
+	}
+
+	// Issues and pull requests are org/repo#number, followed by the title and
+	// state when looked up; a repository is org/repo
+	if number != "" {
+		text := githubAnchorText(fmt.Sprintf("%s#%s", orgRepo, number), metadata)
+		if title := field("title"); title != "" {
+			text += ": " + title
+			if state := field("state"); state != "" {
+				text += fmt.Sprintf(" (%s)", state)
+			}
+		}
+		return text
+	}
+	return orgRepo
+}
+
+// githubAnchorText appends the comment, review or commit a link points at
+// within an issue, pull request or discussion, e.g. "org/repo#12 comment"
+func githubAnchorText(text string, metadata map[string]interface{}) string {
+	anchor, _ := metadata["anchor"].(string)
+	switch anchor {
+	case github.AnchorComment:
+		return text + " comment"
+	case github.AnchorReview:
+		return text + " review"
+	case github.AnchorReviewComment:
+		return text + " review comment"
+	case github.AnchorCommit:
+		sha, _ := metadata["commit"].(string)
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		return fmt.Sprintf("%s commit %s", text, sha)
+	}
+	return text
+}
+
+// writeGitRemote links a remote on a forge other than GitHub to its web page
+func (w *URLWriter) writeGitRemote(ctx *types.ParseContext) (string, error) {
+	name, _ := ctx.Metadata["name"].(string)
+	target, _ := ctx.Metadata["url"].(string)
+	if name == "" || target == "" {
+		return ctx.OriginalInput, nil
+	}
+	return fmt.Sprintf("[%s](%s)", name, target), nil
+}
+
+// writeGitBranch links a branch and the JIRA issue it names, e.g.
+// [API@feature/PLAT-123-sso](...) ([PLAT-123](...)). Without a repository
+// the branch is written as code.
+func (w *URLWriter) writeGitBranch(ctx *types.ParseContext) (string, error) {
+	branch, _ := ctx.Metadata["branch"].(string)
 	issueKey, _ := ctx.Metadata["issue_key"].(string)
-	if issueKey == "" {
+	if branch == "" || issueKey == "" {
+		return ctx.OriginalInput, nil
+	}
+	instance := jiraInstance(w.config, ctx, issueKey)
+	issue := fmt.Sprintf("[%s](%s)", instance.DisplayKey(issueKey), instance.IssueURL(issueKey))
+
+	org, _ := ctx.Metadata["org"].(string)
+	repo, _ := ctx.Metadata["repo"].(string)
+	target, _ := ctx.Metadata["url"].(string)
+	if org == "" || repo == "" || target == "" {
+		return fmt.Sprintf("`%s` (%s)", branch, issue), nil
+	}
+
+	orgRepo := w.githubHost(ctx, org, repo).DisplayName(org, repo)
+	return fmt.Sprintf("[%s@%s](%s) (%s)", orgRepo, branch, target, issue), nil
+}
+
+// githubHost returns the host recorded by the parser, or the most likely
+// host for org/repo when none was
+func (w *URLWriter) githubHost(ctx *types.ParseContext, org, repo string) github.Host {
+	if name, _ := ctx.Metadata["host"].(string); name != "" {
+		if host, ok := w.hosts.Lookup(name); ok {
+			return host
+		}
+	}
+	return w.hosts.ForRepo(org, repo)
+}
+
+// jqlTextLength is how much of a JQL query is shown in link text
+const jqlTextLength = 40
+
+// writeJIRAURL links an issue by its key and other JIRA pages by what they
+// show, e.g. "PLAT board 12", "Filter 10432" or "JQL: project = PLAT…"
+func (w *URLWriter) writeJIRAURL(ctx *types.ParseContext) (string, error) {
+	field := func(key string) string {
+		value, _ := ctx.Metadata[key].(string)
+		return value
+	}
+	issueKey, project := field("issue_key"), field("project")
+	instance := jiraInstance(w.config, ctx, issueKey)
+
+	// Boards, backlogs, sprints and queues belong to a project
+	inProject := func(text string) string {
+		if project == "" {
+			return strings.ToUpper(text[:1]) + text[1:]
+		}
+		return instance.DisplayKey(project) + " " + text
+	}
+
+	var text string
+	switch {
+	case issueKey != "":
+		text = instance.DisplayKey(issueKey)
+	case field("type") == jira.KindBoard:
+		text = inProject("board " + field("board_id"))
+	case field("type") == jira.KindBacklog:
+		text = inProject("backlog")
+	case field("type") == jira.KindSprint:
+		text = inProject("sprint " + field("sprint_id"))
+	case field("type") == jira.KindQueue:
+		text = inProject("queue " + field("queue_id"))
+	case field("type") == jira.KindFilter:
+		text = "Filter " + field("filter_id")
+	case field("type") == jira.KindSearch:
+		text = "JQL: " + truncateText(field("jql"), jqlTextLength)
+	default:
 		return w.writeGenericURL(ctx)
 	}
+	return fmt.Sprintf("[%s](%s)", text, ctx.OriginalInput), nil
+}
 
-	return fmt.Sprintf("[%s](%s)", issueKey, ctx.OriginalInput), nil
+// truncateText shortens text to at most limit runes, ending in "…" when
+// cut, at a word break where there is one
+func truncateText(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	cut := string(runes[:limit-1])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimSpace(cut) + "…"
 }
 
 func (w *URLWriter) writeJIRACommentURL(ctx *types.ParseContext) (string, error) {
@@ -179,7 +457,8 @@ func (w *URLWriter) writeJIRACommentURL(ctx *types.ParseContext) (string, error)
 		return w.writeGenericURL(ctx)
 	}
 
-	return fmt.Sprintf("[%s comment](%s)", issueKey, ctx.OriginalInput), nil
+	displayKey := jiraInstance(w.config, ctx, issueKey).DisplayKey(issueKey)
+	return fmt.Sprintf("[%s comment](%s)", displayKey, ctx.OriginalInput), nil
 }
 
 func (w *URLWriter) writeJenkinsURL(ctx *types.ParseContext) (string, error) {
@@ -214,7 +493,7 @@ func (w *URLWriter) writeYouTubeURL(ctx *types.ParseContext) (string, error) {
 		icon = "🎥🗃️"
 	}
 
-	linkText := fmt.Sprintf("%s %s", icon, title)
+	linkText := fmt.Sprintf("%s %s", icon, pageTitle(title))
 	return fmt.Sprintf("[%s](%s)", linkText, ctx.OriginalInput), nil
 }
 
@@ -224,7 +503,7 @@ func (w *URLWriter) writeNotionURL(ctx *types.ParseContext) (string, error) {
 		return w.writeGenericURL(ctx)
 	}
 
-	return fmt.Sprintf("[%s](%s)", title, ctx.OriginalInput), nil
+	return fmt.Sprintf("[%s](%s)", pageTitle(title), ctx.OriginalInput), nil
 }
 
 func (w *URLWriter) writeMiniMaxURL(ctx *types.ParseContext) (string, error) {
@@ -296,6 +575,12 @@ func (w *URLWriter) writeGenericURL(ctx *types.ParseContext) (string, error) {
 		return ctx.OriginalInput, nil
 	}
 
+	// Prefer a page title supplied by the enrichment stage. Types with a
+	// format of their own only get here when that format doesn't apply.
+	if title, _ := ctx.Metadata["title"].(string); title != "" {
+		return fmt.Sprintf("[%s](%s)", pageTitle(title), ctx.OriginalInput), nil
+	}
+
 	// Extract domain, removing common prefixes
 	domain := u.Host
 	domain = strings.TrimPrefix(domain, "www.")
@@ -319,6 +604,15 @@ func (w *URLWriter) writeGenericURL(ctx *types.ParseContext) (string, error) {
 	return fmt.Sprintf("[%s](%s)", linkText, ctx.OriginalInput), nil
 }
 
+// linkTextEscaper escapes the characters that would end link text early
+var linkTextEscaper = strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`)
+
+// pageTitle makes a title fetched from a page safe as link text: whitespace,
+// newlines included, collapses to single spaces and brackets are escaped
+func pageTitle(title string) string {
+	return linkTextEscaper.Replace(strings.Join(strings.Fields(title), " "))
+}
+
 var leadingJiraKeyRegex = regexp.MustCompile(`^\s*(\[[A-Z][A-Z0-9]+-\d+\]\s*|[A-Z][A-Z0-9]+-\d+:\s*)`)
 
 func stripLeadingJiraKey(title string) string {
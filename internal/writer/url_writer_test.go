@@ -17,6 +17,10 @@ func TestURLWriter_Vote(t *testing.T) {
 	}{
 		{"GitHub URL", types.ContentTypeGitHubURL, 90},
 		{"GitHub Long", types.ContentTypeGitHubLong, 95},
+		{"GitHub Comment", types.ContentTypeGitHubComment, 95},
+		{"Git Remote", types.ContentTypeGitRemote, 90},
+		{"Git Branch", types.ContentTypeGitBranch, 90},
+		{"GitHub List", types.ContentTypeGitHubList, 95},
 		{"JIRA URL", types.ContentTypeJIRAURL, 90},
 		{"JIRA Comment", types.ContentTypeJIRAComment, 95},
 		{"Jenkins URL", types.ContentTypeJenkinsURL, 90},
@@ -85,6 +89,61 @@ func TestURLWriter_WriteGitHubURL(t *testing.T) {
 			originalInput:  "https://github.com/CompanyCam/Company-Cam-API/pull/15217",
 			expectedOutput: "[CompanyCam/API#15217](https://github.com/CompanyCam/Company-Cam-API/pull/15217)",
 		},
+		{
+			name: "GitHub PR with pattern mapping",
+			config: &types.Config{
+				GitHub: types.GitHubConfig{
+					Mappings: map[string]string{
+						"companycam/companycam-(.*)": "CC/$1",
+						"companycam/*":               "CompanyCam/*",
+					},
+				},
+			},
+			metadata: map[string]interface{}{
+				"org":    "CompanyCam",
+				"repo":   "companycam-mobile",
+				"number": "6549",
+			},
+			originalInput:  "https://github.com/CompanyCam/companycam-mobile/pull/6549",
+			expectedOutput: "[CC/mobile#6549](https://github.com/CompanyCam/companycam-mobile/pull/6549)",
+		},
+		{
+			name: "GitHub clone URL with mapping",
+			config: &types.Config{
+				GitHub: types.GitHubConfig{
+					Mappings: map[string]string{
+						"companycam/company-cam-api": "CompanyCam/API",
+					},
+				},
+			},
+			metadata: map[string]interface{}{
+				"org":  "CompanyCam",
+				"repo": "Company-Cam-API",
+				"url":  "https://github.com/CompanyCam/Company-Cam-API",
+			},
+			originalInput:  "git@github.com:CompanyCam/Company-Cam-API.git",
+			expectedOutput: "[CompanyCam/API](https://github.com/CompanyCam/Company-Cam-API)",
+		},
+		{
+			name: "GitHub PR with title and state",
+			config: &types.Config{
+				GitHub: types.GitHubConfig{
+					Mappings: map[string]string{
+						"companycam/company-cam-api": "CompanyCam/API",
+					},
+				},
+			},
+			metadata: map[string]interface{}{
+				"org":    "CompanyCam",
+				"repo":   "Company-Cam-API",
+				"type":   "pull",
+				"number": "15217",
+				"title":  "Fix SSO logging",
+				"state":  "merged",
+			},
+			originalInput:  "https://github.com/CompanyCam/Company-Cam-API/pull/15217",
+			expectedOutput: "[CompanyCam/API#15217: Fix SSO logging (merged)](https://github.com/CompanyCam/Company-Cam-API/pull/15217)",
+		},
 		{
 			name: "GitHub Issue",
 			config: &types.Config{
@@ -180,6 +239,43 @@ func TestURLWriter_WriteGitHubURL(t *testing.T) {
 			originalInput:  "https://github.com/CompanyCam/Company-Cam-API/commit/def456789abcdef123456789abcdef12345",
 			expectedOutput: "[CompanyCam/API#def4567](https://github.com/CompanyCam/Company-Cam-API/commit/def456789abcdef123456789abcdef12345)",
 		},
+		{
+			name: "GitHub Enterprise uses its own mappings",
+			config: &types.Config{
+				GitHub: types.GitHubConfig{
+					Mappings: map[string]string{"platform/api": "Public API"},
+					Hosts: []types.GitHubHostConfig{{
+						Host:     "github.acme.com",
+						Mappings: map[string]string{"platform/api": "API"},
+					}},
+				},
+			},
+			metadata: map[string]interface{}{
+				"host":   "github.acme.com",
+				"org":    "platform",
+				"repo":   "api",
+				"number": "12",
+			},
+			originalInput:  "https://github.acme.com/platform/api/pull/12",
+			expectedOutput: "[API#12](https://github.acme.com/platform/api/pull/12)",
+		},
+		{
+			name: "GitHub shorthand links to its expanded URL",
+			config: &types.Config{
+				GitHub: types.GitHubConfig{
+					Mappings: map[string]string{"companycam/company-cam-api": "CompanyCam/API"},
+				},
+			},
+			metadata: map[string]interface{}{
+				"org":    "CompanyCam",
+				"repo":   "Company-Cam-API",
+				"type":   "issues",
+				"number": "15217",
+				"url":    "https://github.com/CompanyCam/Company-Cam-API/issues/15217",
+			},
+			originalInput:  "API#15217",
+			expectedOutput: "[CompanyCam/API#15217](https://github.com/CompanyCam/Company-Cam-API/issues/15217)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -233,6 +329,84 @@ func TestURLWriter_WriteJIRAURL(t *testing.T) {
 			originalInput:  "https://companycam.atlassian.net/browse/PLAT-192?focusedCommentId=20266",
 			expectedOutput: "[PLAT-192 comment](https://companycam.atlassian.net/browse/PLAT-192?focusedCommentId=20266)",
 		},
+		{
+			name:        "JIRA board",
+			contentType: types.ContentTypeJIRAURL,
+			metadata: map[string]interface{}{
+				"type":     "board",
+				"project":  "PLAT",
+				"board_id": "12",
+			},
+			originalInput:  "https://companycam.atlassian.net/jira/software/c/projects/PLAT/boards/12",
+			expectedOutput: "[PLAT board 12](https://companycam.atlassian.net/jira/software/c/projects/PLAT/boards/12)",
+		},
+		{
+			name:        "JIRA board without a project",
+			contentType: types.ContentTypeJIRAURL,
+			metadata: map[string]interface{}{
+				"type":     "backlog",
+				"board_id": "7",
+			},
+			originalInput:  "https://jira.example.com/secure/RapidBoard.jspa?rapidView=7&view=planning",
+			expectedOutput: "[Backlog](https://jira.example.com/secure/RapidBoard.jspa?rapidView=7&view=planning)",
+		},
+		{
+			name:        "JIRA sprint",
+			contentType: types.ContentTypeJIRAURL,
+			metadata: map[string]interface{}{
+				"type":      "sprint",
+				"project":   "PLAT",
+				"board_id":  "12",
+				"sprint_id": "34",
+			},
+			originalInput:  "https://companycam.atlassian.net/jira/software/c/projects/PLAT/boards/12/reports/sprint-retrospective?sprint=34",
+			expectedOutput: "[PLAT sprint 34](https://companycam.atlassian.net/jira/software/c/projects/PLAT/boards/12/reports/sprint-retrospective?sprint=34)",
+		},
+		{
+			name:        "JIRA queue",
+			contentType: types.ContentTypeJIRAURL,
+			metadata: map[string]interface{}{
+				"type":     "queue",
+				"project":  "SD",
+				"queue_id": "5",
+			},
+			originalInput:  "https://companycam.atlassian.net/jira/servicedesk/projects/SD/queues/custom/5",
+			expectedOutput: "[SD queue 5](https://companycam.atlassian.net/jira/servicedesk/projects/SD/queues/custom/5)",
+		},
+		{
+			name:        "JIRA filter",
+			contentType: types.ContentTypeJIRAURL,
+			metadata: map[string]interface{}{
+				"type":      "filter",
+				"filter_id": "10432",
+			},
+			originalInput:  "https://companycam.atlassian.net/issues/?filter=10432",
+			expectedOutput: "[Filter 10432](https://companycam.atlassian.net/issues/?filter=10432)",
+		},
+		{
+			name:        "JIRA JQL is truncated at a word",
+			contentType: types.ContentTypeJIRAURL,
+			metadata: map[string]interface{}{
+				"type": "jql",
+				"jql":  "project = PLAT AND status = Done ORDER BY created DESC",
+			},
+			originalInput:  "https://companycam.atlassian.net/issues/?jql=project%20%3D%20PLAT%20AND%20status%20%3D%20Done%20ORDER%20BY%20created%20DESC",
+			expectedOutput: "[JQL: project = PLAT AND status = Done ORDER…](https://companycam.atlassian.net/issues/?jql=project%20%3D%20PLAT%20AND%20status%20%3D%20Done%20ORDER%20BY%20created%20DESC)",
+		},
+		{
+			name:           "Other JIRA page",
+			contentType:    types.ContentTypeJIRAURL,
+			metadata:       map[string]interface{}{"domain": "https://companycam.atlassian.net"},
+			originalInput:  "https://companycam.atlassian.net/jira/your-work",
+			expectedOutput: "[companycam.atlassian.net](https://companycam.atlassian.net/jira/your-work)",
+		},
+		{
+			name:           "Other JIRA page with enriched title",
+			contentType:    types.ContentTypeJIRAURL,
+			metadata:       map[string]interface{}{"domain": "https://companycam.atlassian.net", "title": "Your work"},
+			originalInput:  "https://companycam.atlassian.net/jira/your-work",
+			expectedOutput: "[Your work](https://companycam.atlassian.net/jira/your-work)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -474,6 +648,28 @@ func TestURLWriter_WriteGitHubLongURL(t *testing.T) {
 			originalInput:  "GitHub UI text chunk",
 			expectedOutput: "[upserve/tokenizer#250: Update rack to 2.2.22](https://github.com/upserve/tokenizer/pull/250)",
 		},
+		{
+			name: "GitHub Long on an enterprise host",
+			config: &types.Config{
+				GitHub: types.GitHubConfig{
+					Hosts: []types.GitHubHostConfig{{
+						Host:       "github.acme.com",
+						BaseURL:    "https://git.acme.com",
+						DefaultOrg: "platform",
+					}},
+				},
+			},
+			metadata: map[string]interface{}{
+				"host":   "github.acme.com",
+				"org":    "platform",
+				"repo":   "api",
+				"title":  "Fix login",
+				"number": "31",
+				"type":   "pull",
+			},
+			originalInput:  "GitHub UI text chunk",
+			expectedOutput: "[platform/api#31: Fix login](https://git.acme.com/platform/api/pull/31)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -531,6 +727,30 @@ func TestURLWriter_WriteGenericURL(t *testing.T) {
 			},
 			expectedOutput: "[domain.tld](http://ww3.domain.tld/path/to/document?query=value#anchor)",
 		},
+		{
+			name: "Generic URL with enriched title",
+			config: &types.Config{
+				URL: types.URLConfig{
+					DomainMappings: map[string]string{"example_com": "Example"},
+				},
+			},
+			originalInput: "https://www.example.com/path/to/page",
+			metadata: map[string]interface{}{
+				"domain": "www.example.com",
+				"title":  "Example Page",
+			},
+			expectedOutput: "[Example Page](https://www.example.com/path/to/page)",
+		},
+		{
+			name:          "Enriched title is escaped",
+			config:        &types.Config{},
+			originalInput: "https://example.com/post",
+			metadata: map[string]interface{}{
+				"domain": "example.com",
+				"title":  "Release [v2]\n  notes](https://evil.example)",
+			},
+			expectedOutput: "[Release \\[v2\\] notes\\](https://evil.example)](https://example.com/post)",
+		},
 		{
 			name: "Simple domain (no mapping)",
 			config: &types.Config{
@@ -911,3 +1131,154 @@ func TestURLWriter_WriteGeminiURL(t *testing.T) {
 		})
 	}
 }
+
+func TestGitHubLinkText(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata map[string]interface{}
+		expected string
+	}{
+		{"Blame", map[string]interface{}{"type": "blame", "ref": "main", "path": "go.mod", "lines": "L3"}, "API:go.mod#L3@main (blame)"},
+		{"Tree path", map[string]interface{}{"type": "tree", "ref": "v2", "path": "internal"}, "API:internal@v2"},
+		{"Compare head only", map[string]interface{}{"type": "compare", "head": "feature"}, "API compare feature"},
+		{"Latest release", map[string]interface{}{"type": "release"}, "API latest release"},
+		{"Release asset", map[string]interface{}{"type": "release", "tag": "v1.0", "asset": "api.tar.gz"}, "API release v1.0 (api.tar.gz)"},
+		{"Run attempt", map[string]interface{}{"type": "run", "run_id": "99", "attempt": "2"}, "API Actions run 99 (attempt 2)"},
+		{"Issue list", map[string]interface{}{"type": "issues"}, "API issues"},
+		{"Wiki home", map[string]interface{}{"type": "wiki"}, "API wiki"},
+		{"Issue comment", map[string]interface{}{"type": "issues", "number": "7", "anchor": "comment", "comment_id": "1"}, "API#7 comment"},
+		{"Review", map[string]interface{}{"type": "pull", "number": "12", "anchor": "review", "review_id": "2"}, "API#12 review"},
+		{"Review comment", map[string]interface{}{"type": "pull", "number": "12", "anchor": "review_comment", "review_comment_id": "3"}, "API#12 review comment"},
+		{"Commit in pull request", map[string]interface{}{"type": "pull", "number": "12", "anchor": "commit", "commit": "aa062a602a02d33f"}, "API#12 commit aa062a6"},
+		{"Discussion comment", map[string]interface{}{"type": "discussion", "number": "42", "anchor": "comment", "comment_id": "9"}, "API discussion #42 comment"},
+		{"Unknown page", map[string]interface{}{}, "API"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := githubLinkText("API", tt.metadata); got != tt.expected {
+				t.Errorf("githubLinkText() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestURLWriter_WriteGitBranch(t *testing.T) {
+	cfg := &types.Config{
+		GitHub: types.GitHubConfig{
+			Mappings: map[string]string{"companycam/company-cam-api": "API"},
+		},
+		JIRA: types.JIRAConfig{Domain: "https://companycam.atlassian.net"},
+	}
+	writer := NewURLWriter(cfg)
+
+	tests := []struct {
+		name     string
+		metadata map[string]interface{}
+		expected string
+	}{
+		{
+			name: "Branch in a repository",
+			metadata: map[string]interface{}{
+				"branch":    "feature/PLAT-123-sso",
+				"issue_key": "PLAT-123",
+				"org":       "CompanyCam",
+				"repo":      "Company-Cam-API",
+				"url":       "https://github.com/CompanyCam/Company-Cam-API/tree/feature/PLAT-123-sso",
+			},
+			expected: "[API@feature/PLAT-123-sso](https://github.com/CompanyCam/Company-Cam-API/tree/feature/PLAT-123-sso) ([PLAT-123](https://companycam.atlassian.net/browse/PLAT-123))",
+		},
+		{
+			name: "Branch without a repository",
+			metadata: map[string]interface{}{
+				"branch":    "feature/PLAT-123-sso",
+				"issue_key": "PLAT-123",
+			},
+			expected: "`feature/PLAT-123-sso` ([PLAT-123](https://companycam.atlassian.net/browse/PLAT-123))",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &types.ParseContext{
+				OriginalInput: "feature/PLAT-123-sso",
+				DetectedType:  types.ContentTypeGitBranch,
+				Metadata:      tt.metadata,
+			}
+			got, err := writer.Write(ctx)
+			if err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Write() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGitHubDetails(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata map[string]interface{}
+		expected string
+	}{
+		{"Merged", map[string]interface{}{"state": "merged", "merged_by": "alice", "head": "feature-x", "base": "main"}, "merged by @alice, feature-x → main"},
+		{"Open", map[string]interface{}{"state": "open", "author": "bob", "head": "sso", "base": "main"}, "opened by @bob, sso → main"},
+		{"Draft", map[string]interface{}{"state": "draft", "author": "carol"}, "draft by @carol"},
+		{"Closed keeps only the state", map[string]interface{}{"state": "closed", "author": "dave"}, "closed"},
+		{"Author without state", map[string]interface{}{"author": "erin"}, "by @erin"},
+		{"Nothing known", map[string]interface{}{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := githubDetails(tt.metadata); got != tt.expected {
+				t.Errorf("githubDetails() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestURLWriter_WriteGitHubLongURL_PRDetails(t *testing.T) {
+	metadata := map[string]interface{}{
+		"org": "CompanyCam", "repo": "Company-Cam-API", "title": "Fix SSO logging", "number": "15217", "type": "pull",
+		"state": "merged", "merged_by": "alice", "head": "feature-x", "base": "main",
+	}
+	ctx := &types.ParseContext{DetectedType: types.ContentTypeGitHubLong, Metadata: metadata}
+	cfg := &types.Config{GitHub: types.GitHubConfig{Mappings: map[string]string{"companycam/company-cam-api": "API"}}}
+
+	plain, _ := NewURLWriter(cfg).Write(ctx)
+	if expected := "[API#15217: Fix SSO logging](https://github.com/CompanyCam/Company-Cam-API/pull/15217)"; plain != expected {
+		t.Errorf("Write() = %q, want %q", plain, expected)
+	}
+
+	cfg.GitHub.PRDetails = true
+	rich, _ := NewURLWriter(cfg).Write(ctx)
+	if expected := "[API#15217: Fix SSO logging](https://github.com/CompanyCam/Company-Cam-API/pull/15217) — merged by @alice, feature-x → main"; rich != expected {
+		t.Errorf("Write() with pr_details = %q, want %q", rich, expected)
+	}
+}
+
+func TestURLWriter_WriteGitHubList(t *testing.T) {
+	items := []map[string]string{
+		{"host": "github.com", "org": "CompanyCam", "repo": "Company-Cam-API", "number": "6549", "title": "Fix SSO logging", "type": "issues", "state": "open", "labels": "bug"},
+		{"host": "github.com", "org": "CompanyCam", "repo": "Company-Cam-API", "number": "6548", "title": "Add caching", "type": "pull", "state": "", "labels": ""},
+	}
+	ctx := &types.ParseContext{DetectedType: types.ContentTypeGitHubList, Metadata: map[string]interface{}{"items": items}}
+	cfg := &types.Config{GitHub: types.GitHubConfig{Mappings: map[string]string{"companycam/company-cam-api": "API"}}}
+
+	got, _ := NewURLWriter(cfg).Write(ctx)
+	expected := "- [API#6549: Fix SSO logging](https://github.com/CompanyCam/Company-Cam-API/issues/6549)\n" +
+		"- [API#6548: Add caching](https://github.com/CompanyCam/Company-Cam-API/pull/6548)"
+	if got != expected {
+		t.Errorf("Write() = %q, want %q", got, expected)
+	}
+
+	cfg.GitHub.PRDetails = true
+	got, _ = NewURLWriter(cfg).Write(ctx)
+	expected = "- [API#6549: Fix SSO logging](https://github.com/CompanyCam/Company-Cam-API/issues/6549) — open · bug\n" +
+		"- [API#6548: Add caching](https://github.com/CompanyCam/Company-Cam-API/pull/6548)"
+	if got != expected {
+		t.Errorf("Write() with pr_details = %q, want %q", got, expected)
+	}
+}
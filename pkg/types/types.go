@@ -1,5 +1,11 @@
 package types
 
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
 // ParseContext holds data collected during the parsing phase
 type ParseContext struct {
 	OriginalInput string
@@ -35,14 +41,67 @@ const (
 	ContentTypeCodexThread
 	ContentTypeCircleCI
 	ContentTypeChatGPT
+	ContentTypeGitHubComment
+	ContentTypeGitRemote
+	ContentTypeGitBranch
+	ContentTypeGitHubList
 )
 
-// Parser interface for content detection and parsing
+// contentTypeNames are stable names for content types, used wherever a type
+// is shown or stored (e.g. the history log) rather than its number
+var contentTypeNames = map[ContentType]string{
+	ContentTypeUnknown:                "unknown",
+	ContentTypeURL:                    "url",
+	ContentTypeGitHubURL:              "github_url",
+	ContentTypeGitHubLong:             "github_long",
+	ContentTypeJIRAURL:                "jira_url",
+	ContentTypeJIRAComment:            "jira_comment",
+	ContentTypeNotionURL:              "notion_url",
+	ContentTypeJenkinsURL:             "jenkins_url",
+	ContentTypeYouTubeURL:             "youtube_url",
+	ContentTypeCodeCommitURL:          "codecommit_url",
+	ContentTypeCodeCommitLong:         "codecommit_long",
+	ContentTypeJIRAKey:                "jira_key",
+	ContentTypeJIRAKeyWithDescription: "jira_key_with_description",
+	ContentTypePhone7Digit:            "phone_7_digit",
+	ContentTypePhone10Digit:           "phone_10_digit",
+	ContentTypePhone11Digit:           "phone_11_digit",
+	ContentTypeRaycastURI:             "raycast_uri",
+	ContentTypeOpenCodeSession:        "opencode_session",
+	ContentTypeMiniMaxURL:             "minimax_url",
+	ContentTypeGeminiURL:              "gemini_url",
+	ContentTypeCodexThread:            "codex_thread",
+	ContentTypeCircleCI:               "circleci",
+	ContentTypeChatGPT:                "chatgpt",
+	ContentTypeGitHubComment:          "github_comment",
+	ContentTypeGitRemote:              "git_remote",
+	ContentTypeGitBranch:              "git_branch",
+	ContentTypeGitHubList:             "github_list",
+}
+
+// String returns the stable name of the content type
+func (c ContentType) String() string {
+	if name, ok := contentTypeNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("ContentType(%d)", int(c))
+}
+
+// Parser interface for content detection and parsing.
+// Parse must honour ctx: once it is done, any network lookups should be
+// abandoned and the parser should return its offline result promptly.
 type Parser interface {
-	Parse(input string) (*ParseContext, error)
+	Parse(ctx context.Context, input string) (*ParseContext, error)
 	CanHandle(input string) bool
 }
 
+// Enricher interface for the optional post-parse stage that adds metadata
+// (e.g. page titles) to parsed content, typically via network lookups
+type Enricher interface {
+	Enrich(ctx context.Context, parsed *ParseContext) error
+	GetName() string
+}
+
 // Writer interface for output generation
 type Writer interface {
 	Write(ctx *ParseContext) (string, error)
@@ -56,19 +115,131 @@ type Config struct {
 	JIRA    JIRAConfig    `yaml:"jira" mapstructure:"jira"`
 	Jenkins JenkinsConfig `yaml:"jenkins" mapstructure:"jenkins"`
 	URL     URLConfig     `yaml:"url" mapstructure:"url"`
+
+	Enrichment EnrichmentConfig `yaml:"enrichment" mapstructure:"enrichment"`
+	Cache      CacheConfig      `yaml:"cache" mapstructure:"cache"`
+	Network    NetworkConfig    `yaml:"network" mapstructure:"network"`
+	History    HistoryConfig    `yaml:"history" mapstructure:"history"`
+
+	// ParseTimeout is the global deadline for the parsing phase (e.g. "800ms")
+	ParseTimeout time.Duration `yaml:"parse_timeout" mapstructure:"parse_timeout"`
+
+	// Profiles hold named overrides of the settings above
+	Profiles map[string]ProfileConfig `yaml:"profiles" mapstructure:"profiles"`
+
+	// Profile is the name of the active profile, if any; set when loading
+	Profile string `yaml:"-" mapstructure:"-"`
+
+	// Verbose enables tracing to stderr; set by --verbose
+	Verbose bool `yaml:"-" mapstructure:"-"`
+
+	// NoGitRemote stops input that names no repository from falling back
+	// to the current directory's git remote; set by verify
+	NoGitRemote bool `yaml:"-" mapstructure:"-"`
+}
+
+// ProfileConfig is a named set of overrides layered over the shared
+// settings. Only the keys present in a profile replace shared values.
+type ProfileConfig struct {
+	Match   ProfileMatch  `yaml:"match" mapstructure:"match"`
+	GitHub  GitHubConfig  `yaml:"github" mapstructure:"github"`
+	JIRA    JIRAConfig    `yaml:"jira" mapstructure:"jira"`
+	Jenkins JenkinsConfig `yaml:"jenkins" mapstructure:"jenkins"`
+	URL     URLConfig     `yaml:"url" mapstructure:"url"`
+}
+
+// ProfileMatch holds the rules that select a profile automatically
+type ProfileMatch struct {
+	// Paths selects the profile when the working directory is inside one of them
+	Paths []string `yaml:"paths" mapstructure:"paths"`
+	// Remotes selects the profile when a git remote of the working directory
+	// matches "org", "org/repo" or "host/org/repo" (glob patterns allowed)
+	Remotes []string `yaml:"remotes" mapstructure:"remotes"`
 }
 
-// GitHubConfig holds GitHub-specific configuration
+// GitHubConfig holds GitHub-specific configuration. The top-level settings
+// apply to github.com; Hosts adds GitHub Enterprise and other
+// GitHub-compatible servers.
 type GitHubConfig struct {
+	DefaultOrg  string             `yaml:"default_org" mapstructure:"default_org"`
+	DefaultRepo string             `yaml:"default_repo" mapstructure:"default_repo"`
+	Mappings    map[string]string  `yaml:"mappings" mapstructure:"mappings"`
+	Hosts       []GitHubHostConfig `yaml:"hosts" mapstructure:"hosts"`
+	API         GitHubAPIConfig    `yaml:"api" mapstructure:"api"`
+	// PRDetails adds the state, author and branches found in a copied pull
+	// request page to its link, e.g. "— merged by @alice, feature-x → main",
+	// and the state and labels to each entry of a copied list
+	PRDetails bool `yaml:"pr_details" mapstructure:"pr_details"`
+}
+
+// GitHubAPIConfig holds settings for looking up issue and pull request
+// details with the GitHub REST API
+type GitHubAPIConfig struct {
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+	// Token authenticates requests to github.com; GITHUB_TOKEN or GH_TOKEN
+	// is used when empty
+	Token string `yaml:"token" mapstructure:"token"`
+	// BaseURL is the github.com API, defaulting to https://api.github.com
+	BaseURL string `yaml:"base_url" mapstructure:"base_url"`
+}
+
+// GitHubHostConfig holds the settings for one GitHub-compatible host
+type GitHubHostConfig struct {
+	// Host is the hostname in URLs, e.g. github.example.com
+	Host string `yaml:"host" mapstructure:"host"`
+	// BaseURL is where links are generated, defaulting to https://<host>
+	BaseURL     string            `yaml:"base_url" mapstructure:"base_url"`
 	DefaultOrg  string            `yaml:"default_org" mapstructure:"default_org"`
 	DefaultRepo string            `yaml:"default_repo" mapstructure:"default_repo"`
 	Mappings    map[string]string `yaml:"mappings" mapstructure:"mappings"`
+	// APIURL is the REST API of the host, defaulting to <base_url>/api/v3
+	APIURL string `yaml:"api_url" mapstructure:"api_url"`
+	// Token authenticates API requests to the host
+	Token string `yaml:"token" mapstructure:"token"`
 }
 
-// JIRAConfig holds JIRA-specific configuration
+// JIRAConfig holds JIRA-specific configuration. Domain and Projects
+// describe the main site; further sites are listed under Instances.
 type JIRAConfig struct {
 	Domain   string   `yaml:"domain" mapstructure:"domain"`
 	Projects []string `yaml:"projects" mapstructure:"projects"`
+	// Style is the main site's "cloud" or "server" URL style; see
+	// JIRAInstanceConfig.Style
+	Style string `yaml:"style" mapstructure:"style"`
+	// AnyProject links keys of unlisted projects too, to the main site
+	AnyProject bool `yaml:"any_project" mapstructure:"any_project"`
+	// Exclude lists keys (e.g. "UTF-8") or projects (e.g. "SHA") that are
+	// never linked
+	Exclude   []string             `yaml:"exclude" mapstructure:"exclude"`
+	Discovery JIRADiscoveryConfig  `yaml:"discovery" mapstructure:"discovery"`
+	Instances []JIRAInstanceConfig `yaml:"instances" mapstructure:"instances"`
+}
+
+// JIRADiscoveryConfig holds settings for fetching each site's project list
+// from the Jira REST API, so keys of unlisted projects are linked
+type JIRADiscoveryConfig struct {
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+	// User is the account email for Atlassian Cloud API tokens; without it
+	// Token is sent as a Server/Data Center personal access token
+	User  string `yaml:"user" mapstructure:"user"`
+	Token string `yaml:"token" mapstructure:"token"`
+}
+
+// JIRAInstanceConfig is a further JIRA site, such as a client's self-hosted
+// Jira, with the projects whose keys link to it
+type JIRAInstanceConfig struct {
+	Domain   string   `yaml:"domain" mapstructure:"domain"`
+	Projects []string `yaml:"projects" mapstructure:"projects"`
+	// Prefix is shown before keys from this site, e.g. "Client PLAT-123"
+	Prefix string `yaml:"prefix" mapstructure:"prefix"`
+	// Style is "cloud" or "server" (Server/Data Center), deciding how URLs
+	// are parsed and which REST API discovery uses. By default sites on
+	// atlassian.net are cloud and others server, so a Cloud site on a
+	// custom domain must set it.
+	Style string `yaml:"style" mapstructure:"style"`
+	// User and Token authenticate project discovery on this site
+	User  string `yaml:"user" mapstructure:"user"`
+	Token string `yaml:"token" mapstructure:"token"`
 }
 
 // JenkinsConfig holds Jenkins-specific configuration
@@ -80,3 +251,48 @@ type JenkinsConfig struct {
 type URLConfig struct {
 	DomainMappings map[string]string `yaml:"domain_mappings" mapstructure:"domain_mappings"`
 }
+
+// EnrichmentConfig holds settings for the opt-in page title enrichment stage
+type EnrichmentConfig struct {
+	Enabled  bool          `yaml:"enabled" mapstructure:"enabled"`
+	Timeout  time.Duration `yaml:"timeout" mapstructure:"timeout"`
+	MaxBytes int64         `yaml:"max_bytes" mapstructure:"max_bytes"`
+	// MaxRedirects is nil when unset; 0 follows no redirects
+	MaxRedirects *int `yaml:"max_redirects" mapstructure:"max_redirects"`
+}
+
+// CacheConfig holds settings for the on-disk cache of network lookups
+type CacheConfig struct {
+	Disabled    bool                     `yaml:"disabled" mapstructure:"disabled"`
+	Dir         string                   `yaml:"dir" mapstructure:"dir"`
+	MaxEntries  int                      `yaml:"max_entries" mapstructure:"max_entries"`
+	NegativeTTL time.Duration            `yaml:"negative_ttl" mapstructure:"negative_ttl"`
+	TTL         map[string]time.Duration `yaml:"ttl" mapstructure:"ttl"`
+}
+
+// HistoryConfig holds settings for the local log of transformations
+type HistoryConfig struct {
+	Disabled   bool          `yaml:"disabled" mapstructure:"disabled"`
+	Dir        string        `yaml:"dir" mapstructure:"dir"`
+	MaxEntries int           `yaml:"max_entries" mapstructure:"max_entries"`
+	MaxAge     time.Duration `yaml:"max_age" mapstructure:"max_age"`
+	// HideInput records only a hash of each input, not a preview; the
+	// output is still stored
+	HideInput bool `yaml:"hide_input" mapstructure:"hide_input"`
+}
+
+// Network modes for NetworkConfig.Mode
+const (
+	NetworkModeOff       = "off"
+	NetworkModeAllowlist = "allowlist"
+	NetworkModeOn        = "on"
+)
+
+// NetworkConfig holds the policy applied to every outbound request
+type NetworkConfig struct {
+	Mode      string   `yaml:"mode" mapstructure:"mode"`
+	Allow     []string `yaml:"allow" mapstructure:"allow"`
+	Deny      []string `yaml:"deny" mapstructure:"deny"`
+	Proxy     string   `yaml:"proxy" mapstructure:"proxy"`
+	UserAgent string   `yaml:"user_agent" mapstructure:"user_agent"`
+}